@@ -1,69 +1,228 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/api"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/bot"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
 	"github.com/joho/godotenv"
 )
 
+// shutdownTimeout is how long shutting down may take. Docker waits 10 seconds before killing the process.
+const shutdownTimeout = 8 * time.Second
+
+// usage describes the subcommands.
+const usage = `Usage: shadow-empire-bot [command]
+
+Commands:
+  run [--dry-run]           Monitor the watch directory and notify players (the default)
+  validate                  Check the configuration, watch directory and state, exiting non-zero on problems
+  status                    Print the current game state
+  parse <filename>          Show how a save's filename would be interpreted
+  notify-test               Send a test message to the channel webhook and to players notified by DM
+  simulate <script>         Replay a scripted sequence of file events and check the notifications
+  rollback <turn> <player>  Restore an archived save and rewind the game to it
+  stats [days]              Print each player's turn time statistics`
+
 func main() {
+	env := loadEnvironment()
+
+	// Run the given subcommand, or start the bot if there isn't one
+	command, args := "run", []string{}
+	if len(os.Args) > 1 {
+		command, args = os.Args[1], os.Args[2:]
+	}
+
+	switch command {
+	case "run":
+		run(args, env)
+	case "validate":
+		os.Exit(runValidate(args))
+	case "status":
+		os.Exit(runStatus(args))
+	case "parse":
+		os.Exit(runParse(args))
+	case "notify-test":
+		os.Exit(runNotifyTest(args))
+	case "simulate":
+		os.Exit(runSimulate(args))
+	case "rollback":
+		os.Exit(runRollback(args))
+	case "stats":
+		os.Exit(runStats(args))
+	case "help", "-h", "--help":
+		fmt.Println(usage)
+	default:
+		fmt.Printf("❌ Unknown command: %s\n", command)
+		fmt.Println(usage)
+		os.Exit(2)
+	}
+}
+
+// loadEnvironment loads variables from a .env file if the required ones are not already set. It returns
+// the file the variables were loaded from, or nil if they come from the system.
+func loadEnvironment() *envFile {
 	// Check if required environment variables exist
-	if os.Getenv("USER_MAPPINGS") == "" || os.Getenv("GAME_NAME") == "" {
-		// If not, try to load from .env file
-		envPath := filepath.Join(".", ".env")
-		if _, err := os.Stat(envPath); err == nil {
-			fmt.Println("📝 Loading environment variables from .env file")
-			err := godotenv.Load()
-			if err != nil {
-				log.Printf("⚠️ Error loading .env file: %v", err)
-			}
-		} else {
-			fmt.Println("⚠️ No .env file found and required environment variables not set")
-		}
-	} else {
+	if os.Getenv("USER_MAPPINGS") != "" && os.Getenv("GAME_NAME") != "" {
 		fmt.Println("🔧 Using environment variables from system")
+		return nil
+	}
+
+	// If not, try to load from .env file
+	envPath := filepath.Join(".", ".env")
+	if _, err := os.Stat(envPath); err != nil {
+		fmt.Println("⚠️ No .env file found and required environment variables not set")
+		return nil
 	}
+	fmt.Println("📝 Loading environment variables from .env file")
+	env := newEnvFile(envPath)
+	if err := godotenv.Load(envPath); err != nil {
+		log.Printf("⚠️ Error loading .env file: %v", err)
+		return nil
+	}
+	return env
+}
 
-	// Check if specific environment variables are set after potential loading
-	if os.Getenv("USER_MAPPINGS") == "" {
-		fmt.Println("⚠️ USER_MAPPINGS environment variable is not set, exiting")
+// run starts the bot and blocks while it monitors the watch directory. If the configuration came from
+// env, it is reloaded when the file changes.
+// Usage: run [--dry-run]
+func run(args []string, env *envFile) {
+	// Invalid values fall back to their defaults, so report them but carry on
+	cfg, err := config.FromEnv()
+	if err != nil {
+		for _, problem := range problems(err) {
+			fmt.Printf("⚠️ %s\n", problem)
+		}
+	}
+	dryRun := false
+	for _, arg := range args {
+		if arg != "--dry-run" {
+			fmt.Println("Usage: shadow-empire-bot run [--dry-run]")
+			os.Exit(2)
+		}
+		dryRun = true
+		cfg.DryRun = true
+	}
+	if len(cfg.Players) == 0 {
+		fmt.Println("⚠️ No players are configured in USER_MAPPINGS, exiting")
 		os.Exit(1)
 	}
 	if os.Getenv("GAME_NAME") == "" {
 		fmt.Println("ℹ️ GAME_NAME environment variable is not set, using default: pbem1")
 	}
-	if os.Getenv("DISCORD_WEBHOOK_URL") == "" {
-		fmt.Println("⚠️ DISCORD_WEBHOOK_URL environment variable is not set, webhook notifications will fail")
-	}
-
-	// Check if WATCH_DIRECTORY is set
 	if os.Getenv("WATCH_DIRECTORY") == "" {
 		fmt.Println("⚠️ WATCH_DIRECTORY environment variable is not set, using default: ./data")
 	}
+	if len(cfg.IgnorePatterns) > 0 {
+		fmt.Printf("🔍 Will ignore files containing patterns: %s\n", strings.Join(cfg.IgnorePatterns, ", "))
+	}
+	fmt.Printf("👀 Monitoring directory: %s\n", cfg.WatchDirectory)
 
-	// Check if IGNORE_PATTERNS is set
-	if os.Getenv("IGNORE_PATTERNS") != "" {
-		fmt.Printf("🔍 Will ignore files containing patterns: %s\n", os.Getenv("IGNORE_PATTERNS"))
+	// In a dry run, notifications are logged instead of sent so a game can be watched without pinging anyone
+	webhook.Configure(cfg)
+	if cfg.DryRun {
+		if cfg.DryRunFile != "" {
+			fmt.Printf("🧪 Dry run: notifications will be written to %s instead of being sent\n", cfg.DryRunFile)
+		} else {
+			fmt.Println("🧪 Dry run: notifications will be logged instead of being sent")
+		}
+	}
+
+	// Load the game state shared by the monitor and the bot
+	state, err := monitor.LoadGameState(cfg)
+	if err != nil {
+		log.Fatalf("❌ Failed to load the game state: %v", err)
 	}
 
-	// Check if FILE_DEBOUNCE_MS is set
-	if os.Getenv("FILE_DEBOUNCE_MS") == "" {
-		fmt.Println("ℹ️ FILE_DEBOUNCE_MS environment variable is not set, using default: 30000 (30 seconds)")
+	// Stop cleanly when the container or terminal asks us to
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Start the Discord bot if bot mode is configured
+	var discordBot *bot.Bot
+	if bot.Enabled(cfg) {
+		discordBot, err = bot.New(state, cfg)
+		if err != nil {
+			log.Fatalf("❌ Failed to configure Discord bot: %v", err)
+		}
+		if err := discordBot.RegisterCommands(); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+		}
+		go func() {
+			if err := discordBot.ListenAndServe(); err != nil {
+				log.Fatalf("❌ Discord interactions endpoint stopped: %v", err)
+			}
+		}()
 	} else {
-		fmt.Printf("⏱️ File debounce time set to %s seconds\n", os.Getenv("FILE_DEBOUNCE_MS"))
+		fmt.Println("ℹ️ DISCORD_PUBLIC_KEY environment variable is not set, bot mode disabled")
 	}
 
-	// Start monitoring the directory, default to "./data"
-	directoryToWatch := os.Getenv("WATCH_DIRECTORY")
-	if directoryToWatch == "" {
-		directoryToWatch = "./data"
+	// Start the status API if it is configured
+	var statusServer *api.Server
+	if api.Enabled(cfg) {
+		statusServer = api.New(state, cfg)
+		go func() {
+			if err := statusServer.ListenAndServe(); err != nil {
+				log.Fatalf("❌ Status API stopped: %v", err)
+			}
+		}()
 	}
-	fmt.Printf("👀 Monitoring directory: %s\n", directoryToWatch)
 
-	// Block and monitor directory
-	monitor.MonitorDirectory(directoryToWatch)
+	// Pick up changes to the configuration while running
+	mon := monitor.New(cfg, state)
+	apply := func(cfg *config.Config) {
+		webhook.Configure(cfg)
+		mon.Reload(cfg)
+		if discordBot != nil {
+			discordBot.Reload(cfg)
+		}
+		if statusServer != nil {
+			statusServer.Reload(cfg)
+		}
+	}
+	go watchConfig(ctx, env, apply, cfg, dryRun)
+
+	// Block and monitor directory until a signal arrives
+	if err := mon.Run(ctx); err != nil {
+		fmt.Printf("❌ Error monitoring directory: %v\n", err)
+	}
+	stop()
+	shutdown(discordBot, statusServer, state)
+}
+
+// shutdown stops the servers, waits for notifications still being sent and saves the state, giving up
+// after shutdownTimeout so the process exits before the container is killed.
+func shutdown(discordBot *bot.Bot, statusServer *api.Server, state *monitor.GameState) {
+	fmt.Println("🛑 Shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if discordBot != nil {
+		if err := discordBot.Shutdown(ctx); err != nil {
+			fmt.Printf("⚠️ Discord interactions endpoint did not stop cleanly: %v\n", err)
+		}
+	}
+	if statusServer != nil {
+		if err := statusServer.Shutdown(ctx); err != nil {
+			fmt.Printf("⚠️ Status API did not stop cleanly: %v\n", err)
+		}
+	}
+	if err := webhook.Wait(ctx); err != nil {
+		fmt.Printf("⚠️ Gave up waiting for notifications to be sent: %v\n", err)
+	}
+	if err := state.Save(); err != nil {
+		fmt.Printf("❌ Failed to save state: %v\n", err)
+	}
+	fmt.Println("👋 Stopped")
 }
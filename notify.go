@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// runNotifyTest sends a test message to the game channel, and a test direct message to each player
+// who gets their notifications by DM. In a dry run the messages are only rendered. It returns 1 if
+// any message couldn't be delivered.
+// Usage: notify-test
+func runNotifyTest(args []string) int {
+	if len(args) != 0 {
+		fmt.Println("Usage: shadow-empire-bot notify-test")
+		return 2
+	}
+
+	cfg, err := config.FromEnv()
+	if err != nil {
+		for _, problem := range problems(err) {
+			fmt.Printf("⚠️ %s\n", problem)
+		}
+	}
+
+	webhook.Configure(cfg)
+
+	failed := false
+	if err := webhook.SendTestWebHook(); err != nil {
+		fmt.Printf("❌ Channel webhook: %v\n", err)
+		failed = true
+	} else {
+		fmt.Println("✅ Channel webhook: test message sent")
+	}
+
+	for _, player := range cfg.Players {
+		if player.Notify != userparser.NotifyDM && player.Notify != userparser.NotifyBoth {
+			continue
+		}
+		if err := webhook.SendTestDirectMessage(player); err != nil {
+			fmt.Printf("❌ Direct message to %s: %v\n", player.Username, err)
+			failed = true
+		} else {
+			fmt.Printf("✅ Direct message to %s: test message sent\n", player.Username)
+		}
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
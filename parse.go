@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// runParse shows how the monitor would interpret a save's filename in the current game state.
+// Usage: parse <filename>
+func runParse(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Usage: shadow-empire-bot parse <filename>")
+		return 2
+	}
+
+	cfg, _ := config.FromEnv() // Problems are reported by validate; only the ignore patterns are needed here
+	state, err := monitor.LoadGameState(cfg)
+	if err != nil {
+		fmt.Printf("❌ Failed to load game state: %v\n", err)
+		return 1
+	}
+
+	report := state.DescribeFile(filepath.Base(args[0]), cfg.IgnorePatterns)
+	fmt.Printf("📄 File: %s\n", report.Name)
+	if report.ClaimedTurn > 0 {
+		fmt.Printf("🔢 Turn in filename: %d\n", report.ClaimedTurn)
+	} else {
+		fmt.Println("🔢 Turn in filename: none")
+	}
+	if report.Player != "" {
+		fmt.Printf("👤 Hands turn %d to %s\n", report.Turn, report.Player)
+		fmt.Printf("➡️ %s would be asked to save for %s (turn %d)\n", report.Player, report.NextPlayer, report.NextTurn)
+	}
+	if report.RenameTo != "" {
+		fmt.Printf("✏️ Should be renamed to %s\n", report.RenameTo)
+	}
+	if report.ExpectedName != "" {
+		fmt.Printf("⏳ The game is waiting for %s\n", report.ExpectedName)
+	}
+
+	switch {
+	case report.Problem == "":
+		fmt.Println("✅ The turn would be handed over")
+	case report.OutOfOrder && !cfg.TurnValidation:
+		fmt.Printf("⚠️ Out of order (%s), but handed over anyway as TURN_VALIDATION is off\n", report.Problem)
+	default:
+		fmt.Printf("🚫 Not handed over: %s\n", report.Problem)
+	}
+	return 0
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/audit"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// errBadRequest marks action errors caused by invalid input.
+var errBadRequest = errors.New("bad request")
+
+// adminAction performs an admin action and describes what it did, for the response, the audit log
+// and the game channel announcement.
+type adminAction func(r *http.Request) (string, error)
+
+// registerAdmin adds the admin endpoints to mux. They answer 404 unless ADMIN_TOKEN is configured, which
+// can be done without a restart.
+func (s *Server) registerAdmin(mux *http.ServeMux) {
+	mux.HandleFunc("POST /api/games/{game}/admin/advance", s.admin("advance", s.advance, true))
+	mux.HandleFunc("POST /api/games/{game}/admin/turn", s.admin("set-turn", s.setTurn, true))
+	mux.HandleFunc("POST /api/games/{game}/admin/players/{player}/sit-out", s.admin("sit-out", s.sitOut(true), true))
+	mux.HandleFunc("POST /api/games/{game}/admin/players/{player}/reinstate", s.admin("reinstate", s.sitOut(false), true))
+	mux.HandleFunc("POST /api/games/{game}/admin/resend", s.admin("resend", s.resend, true))
+	// Pausing and resuming post their own announcements
+	mux.HandleFunc("POST /api/games/{game}/admin/pause", s.admin("pause", s.pause, false))
+	mux.HandleFunc("POST /api/games/{game}/admin/resume", s.admin("resume", s.resume, false))
+}
+
+// admin wraps an action with token authentication, auditing and (if announce is set) an announcement
+// in the game channel.
+func (s *Server) admin(name string, action adminAction, announce bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.token() == "" {
+			http.NotFound(w, r)
+			return
+		}
+		entry := audit.Entry{Actor: actor(r), Action: name, Game: r.PathValue("game")}
+
+		if !s.authorized(r) {
+			entry.Error = "unauthorized"
+			s.record(entry)
+			writeError(w, http.StatusUnauthorized, "missing or invalid admin token")
+			return
+		}
+		if _, ok := s.snapshot(w, r); !ok {
+			return
+		}
+
+		description, err := action(r)
+		entry.Details = description
+		if err != nil {
+			entry.Error = err.Error()
+			s.record(entry)
+			writeError(w, errorStatus(err), err.Error())
+			return
+		}
+		s.record(entry)
+
+		fmt.Printf("🛠️ Admin action %s by %s: %s\n", name, entry.Actor, description)
+		if announce {
+			if err := webhook.SendAdminActionWebHook(entry.Actor, description); err != nil {
+				fmt.Printf("❌ Failed to announce admin action %s: %v\n", name, err)
+			}
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"message": description})
+	}
+}
+
+// authorized checks the request's bearer token against ADMIN_TOKEN.
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	adminToken := s.token()
+	return ok && adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// record writes an audit entry, timestamped now, logging any failure.
+func (s *Server) record(entry audit.Entry) {
+	entry.Time = time.Now().UTC()
+	if err := s.audit.Append(entry); err != nil {
+		fmt.Printf("❌ Failed to write audit log: %v\n", err)
+	}
+}
+
+// actor identifies who made an admin request: the X-Admin-Actor header if set, otherwise the client address.
+func actor(r *http.Request) string {
+	if name := strings.TrimSpace(r.Header.Get("X-Admin-Actor")); name != "" {
+		return name
+	}
+	return r.RemoteAddr
+}
+
+// errorStatus maps an action error to an HTTP status code.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errBadRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, monitor.ErrUnknownPlayer):
+		return http.StatusNotFound
+	case errors.Is(err, monitor.ErrNoCurrentPlayer), errors.Is(err, monitor.ErrTooFewPlayers),
+		errors.Is(err, monitor.ErrGamePaused):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// notified describes an action whose state change succeeded, noting if the player could not be notified.
+func notified(description string, err error) string {
+	if err != nil {
+		return fmt.Sprintf("%s (but the notification failed: %v)", description, err)
+	}
+	return description
+}
+
+// advance passes the turn from the current player to the next one. It is refused while the game is paused.
+func (s *Server) advance(r *http.Request) (string, error) {
+	skipped, next, err := s.state.SkipCurrentPlayer()
+	if errors.Is(err, monitor.ErrNoCurrentPlayer) || errors.Is(err, monitor.ErrGamePaused) {
+		return "", err
+	}
+	return notified(fmt.Sprintf("Turn advanced from %s to %s.", skipped.Username, next.Username), err), nil
+}
+
+// setTurn sets the current turn and player from a JSON body such as {"turn": 7, "player": "bob"}.
+func (s *Server) setTurn(r *http.Request) (string, error) {
+	var body struct {
+		Turn   int    `json:"turn"`
+		Player string `json:"player"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&body); err != nil {
+		return "", fmt.Errorf("%w: expected a JSON body with turn and player: %v", errBadRequest, err)
+	}
+	if body.Turn < 1 || body.Player == "" {
+		return "", fmt.Errorf("%w: turn must be at least 1 and player must be set", errBadRequest)
+	}
+
+	target, err := s.state.SetTurn(body.Turn, body.Player)
+	if target.Username == "" {
+		return "", err
+	}
+	return notified(fmt.Sprintf("It is now %s's turn to play turn %d.", target.Username, body.Turn), err), nil
+}
+
+// sitOut returns an action that takes the player in the path out of the rotation, or reinstates them.
+func (s *Server) sitOut(out bool) adminAction {
+	return func(r *http.Request) (string, error) {
+		target, changed, err := s.state.SetSittingOut(r.PathValue("player"), out)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case !changed && out:
+			return fmt.Sprintf("%s was already sitting out.", target.Username), nil
+		case !changed:
+			return fmt.Sprintf("%s was already in the turn order.", target.Username), nil
+		case out:
+			return fmt.Sprintf("%s is sitting out and will be passed over until reinstated.", target.Username), nil
+		}
+		return fmt.Sprintf("%s is back in the turn order.", target.Username), nil
+	}
+}
+
+// resend sends the current player their turn notification again.
+func (s *Server) resend(r *http.Request) (string, error) {
+	current, err := s.state.ResendTurnNotification()
+	if errors.Is(err, monitor.ErrNoCurrentPlayer) {
+		return "", err
+	}
+	return notified(fmt.Sprintf("Turn notification resent to %s.", current.Username), err), nil
+}
+
+// pause pauses the game. An optional JSON body sets a resume date and a reason, e.g.
+// {"until": "2026-12-27", "reason": "holidays"}. The date may also be a full RFC 3339 time.
+func (s *Server) pause(r *http.Request) (string, error) {
+	var body struct {
+		Until  string `json:"until"`
+		Reason string `json:"reason"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&body); err != nil && !errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("%w: invalid JSON body: %v", errBadRequest, err)
+		}
+	}
+
+	var until time.Time
+	if body.Until != "" {
+		var err error
+		if until, err = parseResumeDate(body.Until); err != nil {
+			return "", fmt.Errorf("%w: %v", errBadRequest, err)
+		}
+		if !until.After(time.Now()) {
+			return "", fmt.Errorf("%w: resume date %s is in the past", errBadRequest, body.Until)
+		}
+	}
+
+	if !s.state.Pause(until, body.Reason) {
+		return "The game was already paused.", nil
+	}
+	if until.IsZero() {
+		return "The game is paused until resumed.", nil
+	}
+	return fmt.Sprintf("The game is paused until %s.", until.Format(time.RFC1123)), nil
+}
+
+// resume resumes a paused game.
+func (s *Server) resume(r *http.Request) (string, error) {
+	if !s.state.Resume() {
+		return "The game was not paused.", nil
+	}
+	return "The game has resumed.", nil
+}
+
+// parseResumeDate accepts an RFC 3339 time or a date, which means midnight local time.
+func parseResumeDate(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("resume date must be YYYY-MM-DD or an RFC 3339 time, got %s", value)
+	}
+	return t, nil
+}
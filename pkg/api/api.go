@@ -0,0 +1,366 @@
+// Package api serves a read-only JSON view of the game state and a web dashboard, plus health and
+// readiness probes.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/audit"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/jsonl"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// staleAfter is how many poll intervals may pass without a poll before the bot is reported as not ready.
+const staleAfter = 3
+
+// Server answers status requests from the game state the directory monitor updates.
+type Server struct {
+	state      *monitor.GameState
+	watchDir   string
+	listenAddr string
+	audit      *jsonl.Log[audit.Entry] // Where admin actions are recorded.
+
+	mu         sync.RWMutex
+	adminToken string // Bearer token for the admin endpoints, empty to disable them.
+
+	server *http.Server
+}
+
+// Enabled reports whether the status API has been configured via STATUS_LISTEN_ADDR.
+func Enabled(cfg *config.Config) bool {
+	return cfg.StatusListenAddr != ""
+}
+
+// New creates a status server for the game on STATUS_LISTEN_ADDR. The watch directory is checked by
+// the readiness probe. The admin endpoints are enabled if ADMIN_TOKEN is set, and audited to ADMIN_AUDIT_LOG.
+func New(state *monitor.GameState, cfg *config.Config) *Server {
+	s := &Server{
+		state:      state,
+		watchDir:   cfg.WatchDirectory,
+		listenAddr: cfg.StatusListenAddr,
+		audit:      jsonl.New[audit.Entry](cfg.AuditLog),
+		adminToken: cfg.AdminToken,
+	}
+	s.server = &http.Server{Addr: s.listenAddr, Handler: s.Handler()}
+	return s
+}
+
+// Reload applies a reloaded configuration. Only ADMIN_TOKEN can change while the server is running.
+func (s *Server) Reload(cfg *config.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.adminToken = cfg.AdminToken
+}
+
+// token returns the bearer token for the admin endpoints, or nothing if they are disabled.
+func (s *Server) token() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.adminToken
+}
+
+// Player is a player in the turn order. DiscordID is only included for requests with the admin token.
+type Player struct {
+	Order      int    `json:"order"`
+	Username   string `json:"username"`
+	DiscordID  string `json:"discord_id,omitempty"`
+	SittingOut bool   `json:"sitting_out,omitempty"`
+}
+
+// Game is the current status of a game.
+type Game struct {
+	Name           string    `json:"name"`
+	Players        []Player  `json:"players"`
+	CurrentTurn    int       `json:"current_turn"`
+	CurrentPlayer  *Player   `json:"current_player"` // Null until the first save has been processed.
+	TurnStarted    time.Time `json:"turn_started,omitzero"`
+	WaitingSeconds int64     `json:"waiting_seconds"` // How long the current player has had the turn, excluding pauses.
+	Waiting        string    `json:"waiting"`         // WaitingSeconds as a human-readable duration.
+	LastSave       string    `json:"last_save"`
+	Paused         bool      `json:"paused"`
+	PausedUntil    time.Time `json:"paused_until,omitzero"`
+	PauseReason    string    `json:"pause_reason,omitempty"`
+	DueAt          time.Time `json:"due_at,omitzero"` // When the current turn must be played by, if there is a turn deadline.
+}
+
+// HistoryEntry is a single hand-over of the turn.
+type HistoryEntry struct {
+	Turn      int       `json:"turn"`
+	Player    string    `json:"player"`
+	SaveFile  string    `json:"save_file"`
+	StartedAt time.Time `json:"started_at"`
+	Skipped   bool      `json:"skipped"`
+}
+
+// Notification is a notification the bot is waiting to send: a rename request it is waiting on, or
+// a reminder or deadline message that falls due at Due.
+type Notification struct {
+	Type   string    `json:"type"` // rename, reminder, deadline_warning or deadline.
+	Player string    `json:"player"`
+	Detail string    `json:"detail"`
+	Since  time.Time `json:"since"`
+	Due    time.Time `json:"due,omitzero"`
+}
+
+// Handler returns the HTTP handler for the status API.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /readyz", s.handleReady)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+	mux.HandleFunc("GET /api/games", s.handleGames)
+	mux.HandleFunc("GET /api/games/{game}", s.handleGame)
+	mux.HandleFunc("GET /api/games/{game}/history", s.handleHistory)
+	mux.HandleFunc("GET /api/games/{game}/notifications", s.handleNotifications)
+	mux.HandleFunc("GET /api/games/{game}/players/{player}/calendar.ics", s.handleCalendar)
+	mux.HandleFunc("GET /api/games/{game}/feed.atom", s.handleFeed)
+	s.registerAdmin(mux)
+	return mux
+}
+
+// ListenAndServe serves the status API on STATUS_LISTEN_ADDR. It blocks until the server fails, or
+// returns nil once Shutdown is called.
+func (s *Server) ListenAndServe() error {
+	fmt.Printf("📡 Status API listening on %s\n", s.listenAddr)
+	if s.token() != "" {
+		fmt.Printf("🛠️ Admin endpoints enabled, audited to %s\n", s.audit.Path())
+	}
+	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting requests and waits for those in progress to finish, or for ctx to be done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// handleHealth reports that the process is up.
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// handleReady reports whether the watch directory is readable and the monitor is polling it successfully.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{"watch_directory": "ok", "last_poll": "ok"}
+	ready := true
+
+	if _, err := os.ReadDir(s.watchDir); err != nil {
+		checks["watch_directory"] = err.Error()
+		ready = false
+	}
+
+	lastPoll, pollErr := s.state.LastPoll()
+	switch {
+	case lastPoll.IsZero():
+		checks["last_poll"] = "no poll has completed yet"
+		ready = false
+	case pollErr != nil:
+		checks["last_poll"] = pollErr.Error()
+		ready = false
+	case time.Since(lastPoll) > staleAfter*monitor.PollInterval:
+		checks["last_poll"] = fmt.Sprintf("last poll was %s ago", time.Since(lastPoll).Round(time.Second))
+		ready = false
+	}
+
+	status, code := "ready", http.StatusOK
+	if !ready {
+		status, code = "not ready", http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, map[string]any{"status": status, "checks": checks})
+}
+
+// handleMetrics writes the bot's metrics in the Prometheus text format. Gauges derived from the
+// game state are refreshed first, so they are current at scrape time.
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	snapshot := s.state.Snapshot()
+	metrics.CurrentTurn.Set(float64(snapshot.CurrentTurn), snapshot.GameName)
+	metrics.TurnWaitingSeconds.Set(snapshot.Waiting(time.Now()).Seconds(), snapshot.GameName)
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	if err := metrics.Write(w); err != nil {
+		fmt.Printf("❌ Error writing metrics: %v\n", err)
+	}
+}
+
+// handleGames lists every game the bot is tracking.
+func (s *Server) handleGames(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, []Game{gameStatus(s.state.Snapshot(), s.authorized(r))})
+}
+
+// handleGame returns the status of a single game.
+func (s *Server) handleGame(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, gameStatus(snapshot, s.authorized(r)))
+}
+
+// handleHistory returns the most recent hand-overs, newest first. ?count limits how many are returned.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	count := len(snapshot.History)
+	if value := r.URL.Query().Get("count"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			writeError(w, http.StatusBadRequest, "count must be a positive integer")
+			return
+		}
+		count = min(count, parsed)
+	}
+
+	history := make([]HistoryEntry, 0, count)
+	for i := len(snapshot.History) - 1; i >= len(snapshot.History)-count; i-- {
+		event := snapshot.History[i]
+		history = append(history, HistoryEntry{
+			Turn:      event.Turn,
+			Player:    event.Player,
+			SaveFile:  event.SaveFile,
+			StartedAt: event.StartedAt,
+			Skipped:   event.Skipped,
+		})
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// handleNotifications returns the notifications the bot is waiting to send: an outstanding rename,
+// the current player's next reminder and, with a turn deadline, the final warning and the deadline
+// itself. Reminders and deadlines are held while the game is paused, so none are listed then.
+func (s *Server) handleNotifications(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, pendingNotifications(snapshot, time.Now()))
+}
+
+// pendingNotifications lists the notifications waiting to be sent in the game, as of now.
+func pendingNotifications(snapshot monitor.Snapshot, now time.Time) []Notification {
+	notifications := []Notification{}
+	if pending := snapshot.PendingRename; pending != nil {
+		notifications = append(notifications, Notification{
+			Type:   "rename",
+			Player: pending.Saver,
+			Detail: fmt.Sprintf("waiting for %s to be renamed to %s", pending.Original, pending.Expected),
+			Since:  pending.Requested,
+		})
+	}
+
+	current, ok := snapshot.CurrentMapping()
+	if !ok || snapshot.Paused {
+		return notifications
+	}
+	if !snapshot.NextReminder.IsZero() {
+		notifications = append(notifications, Notification{
+			Type:   "reminder",
+			Player: current.Username,
+			Detail: fmt.Sprintf("reminder to play turn %d", snapshot.CurrentTurn),
+			Since:  snapshot.TurnStarted,
+			Due:    snapshot.NextReminder,
+		})
+	}
+	dueAt, ok := snapshot.DueAt(now)
+	if !ok || snapshot.DeadlineMissed {
+		return notifications
+	}
+	if !snapshot.DeadlineWarned {
+		notifications = append(notifications, Notification{
+			Type:   "deadline_warning",
+			Player: current.Username,
+			Detail: fmt.Sprintf("final warning, %s before turn %d is due", snapshot.FinalWarning, snapshot.CurrentTurn),
+			Since:  snapshot.TurnStarted,
+			Due:    dueAt.Add(-snapshot.FinalWarning),
+		})
+	}
+	return append(notifications, Notification{
+		Type:   "deadline",
+		Player: current.Username,
+		Detail: fmt.Sprintf("turn %d is due, after which the player is skipped", snapshot.CurrentTurn),
+		Since:  snapshot.TurnStarted,
+		Due:    dueAt,
+	})
+}
+
+// snapshot returns the state of the game named in the request path, writing a 404 if it is not tracked.
+func (s *Server) snapshot(w http.ResponseWriter, r *http.Request) (monitor.Snapshot, bool) {
+	snapshot := s.state.Snapshot()
+	if !strings.EqualFold(r.PathValue("game"), snapshot.GameName) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown game %s", r.PathValue("game")))
+		return snapshot, false
+	}
+	return snapshot, true
+}
+
+// gameStatus converts a snapshot to its JSON representation, with the players' Discord IDs if
+// withDiscordIDs is set.
+func gameStatus(snapshot monitor.Snapshot, withDiscordIDs bool) Game {
+	game := Game{
+		Name:        snapshot.GameName,
+		Players:     make([]Player, 0, len(snapshot.Players)),
+		CurrentTurn: snapshot.CurrentTurn,
+		TurnStarted: snapshot.TurnStarted,
+		LastSave:    snapshot.LastSave,
+		Paused:      snapshot.Paused,
+		PausedUntil: snapshot.PausedUntil,
+		PauseReason: snapshot.PauseReason,
+	}
+	discordID := func(mapping userparser.UserMapping) string {
+		if !withDiscordIDs {
+			return ""
+		}
+		return mapping.DiscordID
+	}
+	for _, mapping := range snapshot.Players {
+		game.Players = append(game.Players, Player{
+			Order:      mapping.Order,
+			Username:   mapping.Username,
+			DiscordID:  discordID(mapping),
+			SittingOut: snapshot.SittingOut[strings.ToLower(mapping.Username)],
+		})
+	}
+	if current, ok := snapshot.CurrentMapping(); ok {
+		game.CurrentPlayer = &Player{Order: current.Order, Username: current.Username, DiscordID: discordID(current)}
+	}
+	if !snapshot.TurnStarted.IsZero() {
+		waiting := snapshot.Waiting(time.Now())
+		game.WaitingSeconds = int64(waiting.Seconds())
+		game.Waiting = waiting.Round(time.Second).String()
+	}
+	if dueAt, ok := snapshot.DueAt(time.Now()); ok {
+		game.DueAt = dueAt
+	}
+	return game
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		fmt.Printf("❌ Error writing status response: %v\n", err)
+	}
+}
+
+// writeError writes a JSON error response.
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, map[string]string{"error": message})
+}
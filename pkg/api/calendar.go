@@ -0,0 +1,157 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// calendarProdID identifies the bot in the iCalendar feeds it generates.
+const calendarProdID = "-//Shadow Empire PBEM Bot//Turn Calendar//EN"
+
+// handleCalendar serves an iCalendar feed for one player. While it is their turn, the feed has an
+// event for the turn with an alarm when it starts, and, if there is a turn deadline, an event for
+// the deadline with an alarm at the final warning. Otherwise the feed is empty.
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+
+	var player string
+	for _, mapping := range snapshot.Players {
+		if strings.EqualFold(mapping.Username, r.PathValue("player")) {
+			player = mapping.Username
+		}
+	}
+	if player == "" {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("unknown player %s", r.PathValue("player")))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", strings.ToLower(player)+".ics"))
+	if _, err := w.Write([]byte(playerCalendar(snapshot, player, time.Now()))); err != nil {
+		fmt.Printf("❌ Error writing calendar: %v\n", err)
+	}
+}
+
+// playerCalendar builds the iCalendar feed for a player.
+func playerCalendar(snapshot monitor.Snapshot, player string, now time.Time) string {
+	cal := &calendar{}
+	cal.line("BEGIN", "VCALENDAR")
+	cal.line("VERSION", "2.0")
+	cal.line("PRODID", calendarProdID)
+	cal.line("CALSCALE", "GREGORIAN")
+	cal.line("METHOD", "PUBLISH")
+	cal.line("X-WR-CALNAME", fmt.Sprintf("%s turns for %s", snapshot.GameName, player))
+	// Ask calendar apps to refresh often, as the feed changes whenever the turn is handed over
+	cal.line("REFRESH-INTERVAL;VALUE=DURATION", "PT15M")
+	cal.line("X-PUBLISHED-TTL", "PT15M")
+
+	current, ok := snapshot.CurrentMapping()
+	if ok && strings.EqualFold(current.Username, player) {
+		uid := fmt.Sprintf("%s-turn%d-%s", strings.ToLower(snapshot.GameName), snapshot.CurrentTurn, strings.ToLower(player))
+		dueAt, hasDeadline := snapshot.DueAt(now)
+
+		description := fmt.Sprintf("It's your turn to play turn %d of %s.", snapshot.CurrentTurn, snapshot.GameName)
+		if snapshot.LastSave != "" {
+			description += fmt.Sprintf(" Load %s.", snapshot.LastSave)
+		}
+		if snapshot.Paused {
+			description += " The game is paused, so the deadline will move back by however long the pause lasts."
+		}
+
+		// The turn itself, spanning until the deadline if there is one
+		cal.line("BEGIN", "VEVENT")
+		cal.line("UID", uid+"@shadow-empire-pbem-bot")
+		cal.line("DTSTAMP", icsTime(now))
+		cal.line("DTSTART", icsTime(snapshot.TurnStarted))
+		if hasDeadline {
+			cal.line("DTEND", icsTime(dueAt))
+		} else {
+			cal.line("DURATION", "PT1H")
+		}
+		cal.line("SUMMARY", icsText(fmt.Sprintf("🎲 Your turn in %s (turn %d)", snapshot.GameName, snapshot.CurrentTurn)))
+		cal.line("DESCRIPTION", icsText(description))
+		cal.alarm("PT0S", "It's your turn!")
+		cal.line("END", "VEVENT")
+
+		if hasDeadline {
+			cal.line("BEGIN", "VEVENT")
+			cal.line("UID", uid+"-deadline@shadow-empire-pbem-bot")
+			cal.line("DTSTAMP", icsTime(now))
+			cal.line("DTSTART", icsTime(dueAt))
+			cal.line("SUMMARY", icsText(fmt.Sprintf("⌛ Turn %d of %s is due", snapshot.CurrentTurn, snapshot.GameName)))
+			cal.line("DESCRIPTION", icsText("If your save hasn't arrived by now, you will be skipped for this round."))
+			if snapshot.FinalWarning > 0 {
+				cal.alarm("-"+icsDuration(snapshot.FinalWarning), fmt.Sprintf("Turn %d is due in %s", snapshot.CurrentTurn, formatDuration(snapshot.FinalWarning)))
+			}
+			cal.alarm("PT0S", fmt.Sprintf("Turn %d is due now", snapshot.CurrentTurn))
+			cal.line("END", "VEVENT")
+		}
+	}
+
+	cal.line("END", "VCALENDAR")
+	return cal.String()
+}
+
+// calendar accumulates the content lines of an iCalendar document.
+type calendar struct {
+	strings.Builder
+}
+
+// line writes a content line, folded so no line is longer than 75 octets as RFC 5545 requires.
+func (c *calendar) line(name, value string) {
+	line := name + ":" + value
+	limit := 75
+	for len(line) > limit {
+		// Don't split a multi-byte character across lines
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		c.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		limit = 74 // Continuation lines start with a space
+	}
+	c.WriteString(line + "\r\n")
+}
+
+// alarm writes a display alarm that fires at trigger, relative to the start of the event.
+func (c *calendar) alarm(trigger, description string) {
+	c.line("BEGIN", "VALARM")
+	c.line("ACTION", "DISPLAY")
+	c.line("TRIGGER", trigger)
+	c.line("DESCRIPTION", icsText(description))
+	c.line("END", "VALARM")
+}
+
+// icsTime formats a time as an iCalendar UTC date-time.
+func icsTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+// icsDuration formats a positive duration as an iCalendar duration, such as PT12H30M.
+func icsDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	value := "PT"
+	if hours := int(d.Hours()); hours > 0 {
+		value += fmt.Sprintf("%dH", hours)
+	}
+	if minutes := int(d.Minutes()) % 60; minutes > 0 {
+		value += fmt.Sprintf("%dM", minutes)
+	}
+	if seconds := int(d.Seconds()) % 60; seconds > 0 || value == "PT" {
+		value += fmt.Sprintf("%dS", seconds)
+	}
+	return value
+}
+
+// icsText escapes a value for an iCalendar text property.
+func icsText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(text)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestCalendarLineFolding(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantLines int
+	}{
+		{"short", "Turn 7", 1},
+		{"exactly 75 octets", strings.Repeat("a", 75-len("SUMMARY:")), 1},
+		{"one octet over", strings.Repeat("a", 76-len("SUMMARY:")), 2},
+		{"continuation lines hold 74 octets", strings.Repeat("a", 75+74-len("SUMMARY:")), 2},
+		{"long", strings.Repeat("a", 300), 5},
+		{"multi-byte characters at the fold", strings.Repeat("é", 100), 3},
+		{"four-byte characters at the fold", strings.Repeat("🎲", 40), 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var cal calendar
+			cal.line("SUMMARY", test.value)
+			output := cal.String()
+
+			lines := strings.Split(strings.TrimSuffix(output, "\r\n"), "\r\n")
+			if len(lines) != test.wantLines {
+				t.Errorf("got %d lines, want %d: %q", len(lines), test.wantLines, output)
+			}
+			for i, line := range lines {
+				if len(line) > 75 {
+					t.Errorf("line %d is %d octets, want at most 75", i+1, len(line))
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a character: %q", i+1, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i+1, line)
+				}
+			}
+			if unfolded := strings.ReplaceAll(output, "\r\n ", ""); unfolded != "SUMMARY:"+test.value+"\r\n" {
+				t.Errorf("unfolded line = %q, want the original", unfolded)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+//go:embed templates/*.html
+var templateFiles embed.FS
+
+// templates holds the dashboard pages, rendered on the server with inline styles only.
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"duration": formatDuration,
+	"time":     func(t time.Time) string { return t.Local().Format("2006-01-02 15:04 MST") },
+}).ParseFS(templateFiles, "templates/*.html"))
+
+// dashboardPage is the data rendered by the dashboard template.
+type dashboardPage struct {
+	Games      []dashboardGame
+	Deliveries []webhook.Delivery
+	Generated  time.Time
+}
+
+// dashboardGame is one game's section of the dashboard.
+type dashboardGame struct {
+	Name        string
+	Turn        int
+	Players     []dashboardPlayer
+	Current     string        // Username of the current player, empty if unknown.
+	Waiting     time.Duration // How long the current player has had the turn.
+	LastSave    string
+	Paused      bool
+	PausedUntil time.Time
+	PauseReason string
+	Timeline    []timelineEntry // Newest first.
+}
+
+// dashboardPlayer is a player in the turn order.
+type dashboardPlayer struct {
+	Order      int
+	Username   string
+	Current    bool
+	SittingOut bool
+}
+
+// timelineEntry is a past or ongoing turn with how long it took, not counting time the game was paused.
+type timelineEntry struct {
+	monitor.TurnEvent
+	Duration time.Duration
+	Ongoing  bool
+}
+
+// handleDashboard renders the web dashboard.
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	page := dashboardPage{
+		Games:      []dashboardGame{dashboardStatus(s.state.Snapshot())},
+		Deliveries: webhook.RecentDeliveries(),
+		Generated:  time.Now(),
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := templates.ExecuteTemplate(w, "dashboard.html", page); err != nil {
+		fmt.Printf("❌ Error rendering dashboard: %v\n", err)
+	}
+}
+
+// dashboardStatus converts a snapshot to its dashboard representation.
+func dashboardStatus(snapshot monitor.Snapshot) dashboardGame {
+	game := dashboardGame{
+		Name:        snapshot.GameName,
+		Turn:        snapshot.CurrentTurn,
+		LastSave:    snapshot.LastSave,
+		Paused:      snapshot.Paused,
+		PausedUntil: snapshot.PausedUntil,
+		PauseReason: snapshot.PauseReason,
+	}
+	for i, mapping := range snapshot.Players {
+		game.Players = append(game.Players, dashboardPlayer{
+			Order:      mapping.Order,
+			Username:   mapping.Username,
+			Current:    i == snapshot.CurrentPlayer,
+			SittingOut: snapshot.SittingOut[strings.ToLower(mapping.Username)],
+		})
+	}
+	if current, ok := snapshot.CurrentMapping(); ok {
+		game.Current = current.Username
+		game.Waiting = snapshot.Waiting(time.Now())
+	}
+
+	// Each turn lasted until the next one started; the latest is still running
+	for i := len(snapshot.History) - 1; i >= 0; i-- {
+		entry := timelineEntry{TurnEvent: snapshot.History[i]}
+		if i == len(snapshot.History)-1 {
+			entry.Duration = snapshot.Waiting(time.Now())
+			entry.Ongoing = true
+		} else {
+			entry.Duration = snapshot.History[i+1].StartedAt.Sub(entry.StartedAt) - entry.Paused
+		}
+		game.Timeline = append(game.Timeline, entry)
+	}
+	return game
+}
+
+// formatDuration rounds a duration to minutes, or seconds if it is shorter than a minute.
+func formatDuration(d time.Duration) string {
+	if d < time.Minute {
+		return d.Round(time.Second).String()
+	}
+	return d.Round(time.Minute).String()
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// feedEntries is the number of events included in the Atom feed.
+const feedEntries = 50
+
+// atomFeed is an Atom 1.0 feed (RFC 4287).
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	ID        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Updated   string       `xml:"updated"`
+	Published string       `xml:"published"`
+	Category  atomCategory `xml:"category"`
+	Author    *atomPerson  `xml:"author,omitempty"`
+	Content   *atomText    `xml:"content,omitempty"`
+}
+
+// handleFeed serves an Atom feed of the game's recent events, newest first, for players who follow
+// the game in a feed reader rather than on Discord.
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	snapshot, ok := s.snapshot(w, r)
+	if !ok {
+		return
+	}
+	recent, err := s.state.RecentEvents(feedEntries)
+	if errors.Is(err, monitor.ErrNoEventLog) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	base := fmt.Sprintf("%s://%s", scheme, r.Host)
+	game := strings.ToLower(snapshot.GameName)
+
+	feed := atomFeed{
+		ID:      "urn:shadow-empire-pbem-bot:" + game,
+		Title:   fmt.Sprintf("%s turns", snapshot.GameName),
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Rel: "self", Href: base + r.URL.Path},
+			{Rel: "alternate", Href: base + "/"},
+		},
+		Author: atomPerson{Name: "Shadow Empire Assistant"},
+	}
+	if len(recent) > 0 {
+		feed.Updated = recent[0].Time.UTC().Format(time.RFC3339)
+	}
+	for _, event := range recent {
+		entry := atomEntry{
+			ID:        "urn:shadow-empire-pbem-bot:event:" + event.ID(),
+			Title:     event.Title,
+			Updated:   event.Time.UTC().Format(time.RFC3339),
+			Published: event.Time.UTC().Format(time.RFC3339),
+			Category:  atomCategory{Term: string(event.Kind)},
+		}
+		if event.Player != "" {
+			entry.Author = &atomPerson{Name: event.Player}
+		}
+		if event.Detail != "" {
+			entry.Content = &atomText{Type: "text", Body: event.Detail}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		fmt.Printf("❌ Error writing feed: %v\n", err)
+		return
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		fmt.Printf("❌ Error writing feed: %v\n", err)
+	}
+}
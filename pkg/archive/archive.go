@@ -0,0 +1,229 @@
+// Package archive keeps a versioned copy of every processed save so a game can be rolled back.
+//
+// Saves are stored as <dir>/<game>/turn-0007/<player><ext> next to a manifest.json describing
+// every archived file.
+package archive
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// manifestName is the manifest file kept in each game's archive directory.
+const manifestName = "manifest.json"
+
+// Entry describes one archived save in the manifest.
+type Entry struct {
+	Turn       int       `json:"turn"`
+	Player     string    `json:"player"`
+	File       string    `json:"file"`        // Path relative to the game's archive directory.
+	SourceName string    `json:"source_name"` // Original filename in the watch directory.
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+// Retention controls which rounds are pruned after a save is archived. A round is one turn number,
+// in which every player takes a turn.
+type Retention struct {
+	KeepRounds int // Keep the most recent N rounds (0 keeps everything).
+	KeepEvery  int // Additionally keep every Nth round forever (0 disables).
+}
+
+// Archive stores saves for a single game.
+type Archive struct {
+	dir       string // The game's archive directory, <root>/<game>.
+	retention Retention
+	mu        sync.Mutex
+}
+
+// New creates an archive for a game under root.
+func New(root, gameName string, retention Retention) *Archive {
+	return &Archive{
+		dir:       filepath.Join(root, gameName),
+		retention: retention,
+	}
+}
+
+// Dir returns the game's archive directory.
+func (a *Archive) Dir() string {
+	return a.dir
+}
+
+// turnDir returns the directory holding a turn's saves, relative to the game directory.
+func turnDir(turn int) string {
+	return fmt.Sprintf("turn-%04d", turn)
+}
+
+// Store copies the save read from src, called name in the watch directory, into the archive for the
+// given turn and player, records it in the manifest as archived at the given time and applies the
+// retention policy.
+func (a *Archive) Store(src io.Reader, name string, turn int, player string, archivedAt time.Time) (Entry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	relPath := filepath.Join(turnDir(turn), player+filepath.Ext(name))
+	destPath := filepath.Join(a.dir, relPath)
+	if err := os.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+		return Entry{}, fmt.Errorf("error creating archive directory: %w", err)
+	}
+
+	hash, size, err := copyFile(src, destPath)
+	if err != nil {
+		return Entry{}, fmt.Errorf("error archiving %s: %w", name, err)
+	}
+
+	entry := Entry{
+		Turn:       turn,
+		Player:     player,
+		File:       filepath.ToSlash(relPath),
+		SourceName: name,
+		SHA256:     hash,
+		Size:       size,
+		ArchivedAt: archivedAt.UTC(),
+	}
+
+	entries, err := a.readManifest()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	// A resubmitted save replaces the earlier copy for the same turn and player
+	kept := entries[:0]
+	for _, existing := range entries {
+		if existing.File != entry.File {
+			kept = append(kept, existing)
+		}
+	}
+	entries = append(kept, entry)
+
+	entries = a.prune(entries)
+	return entry, a.writeManifest(entries)
+}
+
+// Entries returns all archived saves, ordered by turn and then archive time.
+func (a *Archive) Entries() ([]Entry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.readManifest()
+}
+
+// Find returns the archived save for a turn and player (case-insensitive).
+func (a *Archive) Find(turn int, player string) (Entry, bool, error) {
+	entries, err := a.Entries()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, entry := range entries {
+		if entry.Turn == turn && strings.EqualFold(entry.Player, player) {
+			return entry, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// Path returns the absolute path of an archived save.
+func (a *Archive) Path(entry Entry) string {
+	return filepath.Join(a.dir, filepath.FromSlash(entry.File))
+}
+
+// prune removes turns outside the retention policy from disk and from the entry list.
+func (a *Archive) prune(entries []Entry) []Entry {
+	if a.retention.KeepRounds == 0 || len(entries) == 0 {
+		return entries
+	}
+
+	latestTurn := 0
+	for _, entry := range entries {
+		latestTurn = max(latestTurn, entry.Turn)
+	}
+	oldestKept := latestTurn - a.retention.KeepRounds + 1
+
+	kept := entries[:0]
+	prunedTurns := make(map[int]bool)
+	for _, entry := range entries {
+		keepForever := a.retention.KeepEvery > 0 && entry.Turn%a.retention.KeepEvery == 0
+		if entry.Turn >= oldestKept || keepForever {
+			kept = append(kept, entry)
+			continue
+		}
+		if err := os.Remove(a.Path(entry)); err != nil && !os.IsNotExist(err) {
+			fmt.Printf("⚠️ Failed to prune archived save %s: %v\n", entry.File, err)
+			kept = append(kept, entry)
+			continue
+		}
+		prunedTurns[entry.Turn] = true
+	}
+
+	for turn := range prunedTurns {
+		// Only succeeds once the turn directory is empty
+		os.Remove(filepath.Join(a.dir, turnDir(turn)))
+		fmt.Printf("🧹 Pruned archived saves for turn %d\n", turn)
+	}
+	return kept
+}
+
+// readManifest loads the manifest, returning no entries if it does not exist yet.
+func (a *Archive) readManifest() ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(a.dir, manifestName))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading archive manifest: %w", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("error parsing archive manifest: %w", err)
+	}
+	return entries, nil
+}
+
+// writeManifest atomically replaces the manifest with the given entries.
+func (a *Archive) writeManifest(entries []Entry) error {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Turn != entries[j].Turn {
+			return entries[i].Turn < entries[j].Turn
+		}
+		return entries[i].ArchivedAt.Before(entries[j].ArchivedAt)
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling archive manifest: %w", err)
+	}
+
+	tmpPath := filepath.Join(a.dir, manifestName+".tmp")
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("error writing archive manifest: %w", err)
+	}
+	return os.Rename(tmpPath, filepath.Join(a.dir, manifestName))
+}
+
+// copyFile copies src to dest, returning the SHA-256 and size of the copied content.
+func copyFile(src io.Reader, dest string) (string, int64, error) {
+	out, err := os.Create(dest)
+	if err != nil {
+		return "", 0, err
+	}
+
+	h := sha256.New()
+	size, err := io.Copy(io.MultiWriter(out, h), src)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
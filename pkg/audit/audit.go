@@ -0,0 +1,14 @@
+// Package audit defines the entries recorded for administrative actions.
+package audit
+
+import "time"
+
+// Entry is a single recorded action.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Actor   string    `json:"actor"`           // Who performed the action, as reported by the client.
+	Action  string    `json:"action"`          // Name of the action, e.g. "advance" or "pause".
+	Game    string    `json:"game"`            // Game the action applied to.
+	Details string    `json:"details"`         // What the action did.
+	Error   string    `json:"error,omitempty"` // Why the action failed, empty if it succeeded.
+}
@@ -0,0 +1,166 @@
+package bot
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/discord"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// Bot answers Discord slash commands from an HTTP interactions endpoint,
+// backed by the same game state the directory monitor updates.
+type Bot struct {
+	state         *monitor.GameState
+	publicKey     ed25519.PublicKey
+	applicationID string
+	guildID       string
+	listenAddr    string
+
+	mu        sync.RWMutex
+	client    *discord.Client
+	skipRoles []string // Role IDs allowed to /skip, in addition to members who can manage the server.
+
+	server *http.Server
+}
+
+// Enabled reports whether bot mode has been configured via DISCORD_PUBLIC_KEY.
+func Enabled(cfg *config.Config) bool {
+	return cfg.PublicKey != ""
+}
+
+// New creates a bot from the DISCORD_PUBLIC_KEY, DISCORD_APPLICATION_ID, DISCORD_BOT_TOKEN,
+// DISCORD_GUILD_ID, DISCORD_API_BASE_URL, DISCORD_SKIP_ROLES and BOT_LISTEN_ADDR settings in cfg.
+func New(state *monitor.GameState, cfg *config.Config) (*Bot, error) {
+	key, err := hex.DecodeString(cfg.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("DISCORD_PUBLIC_KEY must be a %d byte hex encoded Ed25519 key", ed25519.PublicKeySize)
+	}
+	if cfg.ApplicationID == "" {
+		return nil, fmt.Errorf("DISCORD_APPLICATION_ID is required in bot mode")
+	}
+	if cfg.BotToken == "" {
+		return nil, fmt.Errorf("DISCORD_BOT_TOKEN is required in bot mode")
+	}
+
+	b := &Bot{
+		state:         state,
+		publicKey:     ed25519.PublicKey(key),
+		applicationID: cfg.ApplicationID,
+		guildID:       cfg.GuildID,
+		listenAddr:    cfg.BotListenAddr,
+		client:        discord.NewClient(cfg.APIBaseURL, cfg.BotToken),
+		skipRoles:     cfg.SkipRoles,
+	}
+	b.server = &http.Server{Addr: b.listenAddr, Handler: b.Handler()}
+	return b, nil
+}
+
+// Reload applies a reloaded configuration: the bot token, API base URL and skip roles. The other bot
+// settings only change on a restart.
+func (b *Bot) Reload(cfg *config.Config) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.client = discord.NewClient(cfg.APIBaseURL, cfg.BotToken)
+	b.skipRoles = cfg.SkipRoles
+}
+
+// discordClient returns the client for the Discord REST API.
+func (b *Bot) discordClient() *discord.Client {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.client
+}
+
+// RegisterCommands registers the bot's slash commands with Discord.
+func (b *Bot) RegisterCommands() error {
+	if err := b.discordClient().OverwriteCommands(b.applicationID, b.guildID, commandDefinitions()); err != nil {
+		return fmt.Errorf("failed to register slash commands: %w", err)
+	}
+	fmt.Printf("🤖 Registered %d slash commands\n", len(commandDefinitions()))
+	return nil
+}
+
+// Handler returns the HTTP handler for the Discord interactions endpoint.
+func (b *Bot) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /interactions", b.handleInteraction)
+	return mux
+}
+
+// ListenAndServe serves the interactions endpoint on BOT_LISTEN_ADDR. It blocks until the server
+// fails, or returns nil once Shutdown is called.
+func (b *Bot) ListenAndServe() error {
+	fmt.Printf("🤖 Discord interactions endpoint listening on %s/interactions\n", b.listenAddr)
+	if err := b.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting interactions and waits for those in progress to finish, or for ctx to be done.
+func (b *Bot) Shutdown(ctx context.Context) error {
+	return b.server.Shutdown(ctx)
+}
+
+// handleInteraction verifies and dispatches a single interaction request.
+func (b *Bot) handleInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "error reading body", http.StatusBadRequest)
+		return
+	}
+
+	if !b.verify(r.Header.Get("X-Signature-Ed25519"), r.Header.Get("X-Signature-Timestamp"), body) {
+		http.Error(w, "invalid request signature", http.StatusUnauthorized)
+		return
+	}
+
+	var interaction types.Interaction
+	if err := json.Unmarshal(body, &interaction); err != nil {
+		http.Error(w, "invalid interaction", http.StatusBadRequest)
+		return
+	}
+
+	var response types.InteractionResponse
+	switch interaction.Type {
+	case types.InteractionTypePing:
+		response = types.InteractionResponse{Type: types.InteractionResponsePong}
+	case types.InteractionTypeApplicationCommand:
+		response = b.handleCommand(&interaction)
+	default:
+		http.Error(w, "unsupported interaction type", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		fmt.Printf("❌ Error writing interaction response: %v\n", err)
+	}
+}
+
+// verify checks the Ed25519 signature Discord attaches to every interaction request.
+func (b *Bot) verify(signatureHex, timestamp string, body []byte) bool {
+	signature, err := hex.DecodeString(signatureHex)
+	if err != nil || len(signature) != ed25519.SignatureSize || timestamp == "" {
+		return false
+	}
+	return ed25519.Verify(b.publicKey, append([]byte(timestamp), body...), signature)
+}
+
+// followUp replaces a deferred response with the given content, logging any failure.
+func (b *Bot) followUp(interaction *types.Interaction, content string) {
+	data := &types.InteractionResponseData{Content: content}
+	if err := b.discordClient().EditOriginalResponse(b.applicationID, interaction.Token, data); err != nil {
+		fmt.Printf("❌ Failed to send follow-up for /%s: %v\n", interaction.Data.Name, err)
+	}
+}
@@ -0,0 +1,212 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// defaultHistoryCount is the number of turns shown by /history when no count is given.
+const defaultHistoryCount = 10
+
+// manageGuild is the permission /skip needs by default. Server admins can give other roles access in
+// Discord's integration settings, but those roles must also be listed in DISCORD_SKIP_ROLES.
+var manageGuild = strconv.Itoa(types.PermissionManageGuild)
+
+// commandDefinitions returns the slash commands registered with Discord.
+func commandDefinitions() []types.ApplicationCommand {
+	return []types.ApplicationCommand{
+		{Name: "status", Description: "Show the current turn and whose turn it is"},
+		{Name: "order", Description: "Show the turn order"},
+		{
+			Name:        "history",
+			Description: "Show recent turns",
+			Options: []types.ApplicationCommandOption{
+				{Type: types.CommandOptionInteger, Name: "count", Description: "Number of turns to show"},
+			},
+		},
+		{Name: "skip", Description: "Skip the current player and pass the turn on", DefaultMemberPermissions: &manageGuild},
+		{Name: "remind", Description: "Remind the current player that it's their turn"},
+	}
+}
+
+// handleCommand builds the response for an application command interaction.
+// Commands that send notifications are deferred and answered with a follow-up.
+func (b *Bot) handleCommand(interaction *types.Interaction) types.InteractionResponse {
+	if interaction.Data == nil {
+		return message("❓ Missing command data")
+	}
+
+	fmt.Printf("🤖 Received /%s from %s\n", interaction.Data.Name, invoker(interaction))
+
+	switch interaction.Data.Name {
+	case "status":
+		return message(statusText(b.state.Snapshot()))
+	case "order":
+		return message(orderText(b.state.Snapshot()))
+	case "history":
+		count := defaultHistoryCount
+		if value, ok := intOption(interaction, "count"); ok && value > 0 {
+			count = value
+		}
+		return message(historyText(b.state.Snapshot(), count))
+	case "skip":
+		if !b.canSkip(interaction) {
+			fmt.Printf("🚫 Refused /skip from %s, who can't manage the server and has no skip role\n", invoker(interaction))
+			return message("🚫 Only members who can manage the server, or have a role allowed to skip, can use /skip.")
+		}
+		if _, ok := b.state.Snapshot().CurrentMapping(); !ok {
+			return message(fmt.Sprintf("❌ %v", monitor.ErrNoCurrentPlayer))
+		}
+		go func() {
+			skipped, next, err := b.state.SkipCurrentPlayer()
+			if errors.Is(err, monitor.ErrGamePaused) {
+				b.followUp(interaction, fmt.Sprintf("⏸️ No one can be skipped: %v.", err))
+				return
+			}
+			if errors.Is(err, monitor.ErrNoCurrentPlayer) {
+				b.followUp(interaction, fmt.Sprintf("❌ %v", err))
+				return
+			}
+			reply := fmt.Sprintf("⏭️ Skipped **%s**, **%s** is up now.", skipped.Username, next.Username)
+			if err != nil {
+				reply += fmt.Sprintf("\n⚠️ The notification could not be delivered: %v", err)
+			}
+			b.followUp(interaction, reply)
+		}()
+		return deferred()
+	case "remind":
+		if _, ok := b.state.Snapshot().CurrentMapping(); !ok {
+			return message(fmt.Sprintf("❌ %v", monitor.ErrNoCurrentPlayer))
+		}
+		go func() {
+			current, err := b.state.RemindCurrentPlayer()
+			if err != nil {
+				b.followUp(interaction, fmt.Sprintf("❌ %v", err))
+				return
+			}
+			b.followUp(interaction, fmt.Sprintf("⏰ Reminded **%s**.", current.Username))
+		}()
+		return deferred()
+	default:
+		return message(fmt.Sprintf("❓ Unknown command /%s", interaction.Data.Name))
+	}
+}
+
+// statusText describes the current turn and how long the current player has had it.
+func statusText(snapshot monitor.Snapshot) string {
+	current, ok := snapshot.CurrentMapping()
+	if !ok {
+		return fmt.Sprintf("🎮 **%s**: turn %d, waiting for the first save.", snapshot.GameName, snapshot.CurrentTurn)
+	}
+	text := fmt.Sprintf("🎮 **%s**: turn %d, it's **%s**'s turn (for %s).",
+		snapshot.GameName, snapshot.CurrentTurn, current.Username, snapshot.Waiting(time.Now()).Round(time.Minute))
+	if snapshot.Paused {
+		text += "\n⏸️ The game is paused"
+		if !snapshot.PausedUntil.IsZero() {
+			text += fmt.Sprintf(" until <t:%d:F>", snapshot.PausedUntil.Unix())
+		}
+		text += "."
+	}
+	return text
+}
+
+// orderText lists the players in turn order, marking the current player.
+func orderText(snapshot monitor.Snapshot) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📋 Turn order for **%s**:\n", snapshot.GameName)
+	for i, player := range snapshot.Players {
+		marker := ""
+		if i == snapshot.CurrentPlayer {
+			marker = " ⬅️"
+		}
+		fmt.Fprintf(&sb, "%d. %s%s\n", player.Order, player.Username, marker)
+	}
+	return sb.String()
+}
+
+// historyText lists up to count of the most recent turn hand-overs, newest first.
+func historyText(snapshot monitor.Snapshot, count int) string {
+	if len(snapshot.History) == 0 {
+		return "📜 No turns recorded yet."
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "📜 Recent turns for **%s**:\n", snapshot.GameName)
+	for i := len(snapshot.History) - 1; i >= 0 && count > 0; i, count = i-1, count-1 {
+		event := snapshot.History[i]
+		how := fmt.Sprintf("from `%s`", event.SaveFile)
+		if event.Skipped {
+			how = "after a skip"
+		}
+		fmt.Fprintf(&sb, "- Turn %d: %s, <t:%d:R> %s\n", event.Turn, event.Player, event.StartedAt.Unix(), how)
+	}
+	return sb.String()
+}
+
+// message builds an immediate channel message response.
+func message(content string) types.InteractionResponse {
+	return types.InteractionResponse{
+		Type: types.InteractionResponseChannelMessage,
+		Data: &types.InteractionResponseData{Content: content},
+	}
+}
+
+// deferred builds a response that acknowledges the command and promises a follow-up.
+func deferred() types.InteractionResponse {
+	return types.InteractionResponse{Type: types.InteractionResponseDeferredChannelMessage}
+}
+
+// intOption returns the value of a named integer option, if supplied.
+func intOption(interaction *types.Interaction, name string) (int, bool) {
+	for _, option := range interaction.Data.Options {
+		if option.Name == name {
+			var value int
+			if err := json.Unmarshal(option.Value, &value); err == nil {
+				return value, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// canSkip reports whether the member who sent the interaction may skip players: they must be able to
+// manage the server, or have one of the roles in DISCORD_SKIP_ROLES. Discord hides the command from
+// everyone else by default, but that can be changed by server admins, so it is checked here too.
+func (b *Bot) canSkip(interaction *types.Interaction) bool {
+	member := interaction.Member
+	if member == nil {
+		return false // Not sent from a server, e.g. a DM with the bot
+	}
+	if permissions, err := strconv.ParseUint(member.Permissions, 10, 64); err == nil &&
+		permissions&(types.PermissionAdministrator|types.PermissionManageGuild) != 0 {
+		return true
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, role := range member.Roles {
+		if slices.Contains(b.skipRoles, role) {
+			return true
+		}
+	}
+	return false
+}
+
+// invoker returns the name of the user who triggered the interaction, for logging.
+func invoker(interaction *types.Interaction) string {
+	switch {
+	case interaction.Member != nil && interaction.Member.User != nil:
+		return interaction.Member.User.Username
+	case interaction.User != nil:
+		return interaction.User.Username
+	default:
+		return "unknown user"
+	}
+}
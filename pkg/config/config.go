@@ -0,0 +1,286 @@
+// Package config reads and validates the bot's configuration from environment variables.
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/archive"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/savefile"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/schedule"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// Save inspection modes, set with SAVE_INSPECTION.
+const (
+	InspectionOff    = "off"    // Trust filenames alone.
+	InspectionLog    = "log"    // Log what would be rejected, but hand every save over.
+	InspectionOn     = "on"     // Reject truncated and non-save files, log inconsistencies.
+	InspectionStrict = "strict" // Also reject saves whose content contradicts the filename.
+)
+
+// Automatic rename modes, set with AUTO_RENAME.
+const (
+	AutoRenameOff    = "off"    // Only ask the player to rename the file.
+	AutoRenameRename = "rename" // Rename the file to the expected name.
+	AutoRenameCopy   = "copy"   // Copy the file to the expected name, leaving the original in place.
+)
+
+// Config is the configuration of the directory monitor.
+type Config struct {
+	GameName       string                   // GAME_NAME, as written. Save files are matched regardless of case.
+	Players        []userparser.UserMapping // USER_MAPPINGS, sorted by order. Empty if missing or invalid.
+	WatchDirectory string                   // WATCH_DIRECTORY.
+	WebhookURL     string                   // DISCORD_WEBHOOK_URL.
+	BotToken       string                   // DISCORD_BOT_TOKEN, used to send direct messages.
+	APIBaseURL     string                   // DISCORD_API_BASE_URL, empty for Discord's own API.
+	DryRun         bool                     // DRY_RUN: log notifications instead of sending them.
+	DryRunFile     string                   // DRY_RUN_FILE, empty to log dry-run notifications to the console.
+	ReloadAnnounce bool                     // CONFIG_RELOAD_ANNOUNCE: post configuration changes in the game channel.
+
+	IgnorePatterns []string      // IGNORE_PATTERNS, lowercase.
+	FileDebounce   time.Duration // FILE_DEBOUNCE_MS.
+	FileHashCheck  bool          // FILE_HASH_CHECK.
+	FileCheckTime  time.Duration // FILE_CHECK_TIME.
+	FileAgeLimit   time.Duration // FILE_AGE_LIMIT.
+	SaveInspection string        // SAVE_INSPECTION.
+	SaveMinSize    int64         // SAVE_MIN_SIZE_BYTES.
+	AutoRename     string        // AUTO_RENAME.
+	TurnValidation bool          // TURN_VALIDATION.
+
+	StateFile   string // STATE_FILE, empty to keep the state in memory only.
+	HistoryFile string // HISTORY_FILE, empty to keep no turn history.
+	EventLog    string // EVENT_LOG, empty to record no events.
+	AuditLog    string // ADMIN_AUDIT_LOG.
+
+	StatusListenAddr string // STATUS_LISTEN_ADDR, empty to disable the status API.
+	AdminToken       string // ADMIN_TOKEN, empty to disable the admin endpoints.
+
+	PublicKey     string   // DISCORD_PUBLIC_KEY, hex encoded. Empty to disable bot mode.
+	ApplicationID string   // DISCORD_APPLICATION_ID.
+	GuildID       string   // DISCORD_GUILD_ID, empty to register the slash commands globally.
+	SkipRoles     []string // DISCORD_SKIP_ROLES: role IDs allowed to /skip.
+	BotListenAddr string   // BOT_LISTEN_ADDR.
+
+	ArchiveDirectory string            // ARCHIVE_DIRECTORY, empty to disable the archive.
+	ArchiveRetention archive.Retention // ARCHIVE_KEEP_ROUNDS and ARCHIVE_KEEP_EVERY.
+
+	StatsSummaryInterval time.Duration      // STATS_SUMMARY_INTERVAL, zero to disable.
+	RoundSummary         bool               // ROUND_SUMMARY.
+	DigestSchedule       *schedule.Schedule // DIGEST_SCHEDULE, nil to disable.
+
+	TurnDeadline         time.Duration // TURN_DEADLINE, zero for no deadline.
+	TurnDeadlineWarning  time.Duration // TURN_DEADLINE_WARNING.
+	TurnDeadlineMaxSkips int           // TURN_DEADLINE_MAX_SKIPS, zero for no limit.
+}
+
+// FromEnv reads the configuration from the environment. See Load.
+func FromEnv() (*Config, error) {
+	return Load(os.Getenv)
+}
+
+// Load reads the configuration using lookup (such as os.Getenv) to get each variable. Every problem
+// found is returned in the error, one per line, and the default is used in place of any invalid
+// value so the bot can still run.
+func Load(lookup func(string) string) (*Config, error) {
+	p := &parser{lookup: lookup}
+	cfg := &Config{
+		GameName:       p.text("GAME_NAME", "pbem1"),
+		WatchDirectory: p.text("WATCH_DIRECTORY", "./data"),
+		WebhookURL:     lookup("DISCORD_WEBHOOK_URL"),
+		BotToken:       lookup("DISCORD_BOT_TOKEN"),
+		APIBaseURL:     lookup("DISCORD_API_BASE_URL"),
+		DryRun:         p.boolean("DRY_RUN", false),
+		DryRunFile:     lookup("DRY_RUN_FILE"),
+		ReloadAnnounce: p.boolean("CONFIG_RELOAD_ANNOUNCE", false),
+
+		IgnorePatterns: parseIgnorePatterns(lookup("IGNORE_PATTERNS")),
+		FileDebounce:   time.Duration(p.integer("FILE_DEBOUNCE_MS", 30000)) * time.Millisecond,
+		FileHashCheck:  p.boolean("FILE_HASH_CHECK", false),
+		FileCheckTime:  p.duration("FILE_CHECK_TIME", 24*time.Hour),
+		FileAgeLimit:   p.duration("FILE_AGE_LIMIT", 24*time.Hour),
+		SaveInspection: p.choice("SAVE_INSPECTION", InspectionLog, InspectionOff, InspectionLog, InspectionOn, InspectionStrict),
+		SaveMinSize:    int64(p.integer("SAVE_MIN_SIZE_BYTES", savefile.DefaultMinSize)),
+		AutoRename:     p.choice("AUTO_RENAME", AutoRenameOff, AutoRenameOff, AutoRenameRename, AutoRenameCopy),
+		TurnValidation: p.boolean("TURN_VALIDATION", true),
+
+		StateFile:   lookup("STATE_FILE"),
+		HistoryFile: lookup("HISTORY_FILE"),
+		EventLog:    lookup("EVENT_LOG"),
+		AuditLog:    p.text("ADMIN_AUDIT_LOG", "./audit.jsonl"),
+
+		StatusListenAddr: lookup("STATUS_LISTEN_ADDR"),
+		AdminToken:       lookup("ADMIN_TOKEN"),
+
+		PublicKey:     lookup("DISCORD_PUBLIC_KEY"),
+		ApplicationID: lookup("DISCORD_APPLICATION_ID"),
+		GuildID:       lookup("DISCORD_GUILD_ID"),
+		SkipRoles:     parseList(lookup("DISCORD_SKIP_ROLES")),
+		BotListenAddr: p.text("BOT_LISTEN_ADDR", ":8080"),
+
+		ArchiveDirectory: lookup("ARCHIVE_DIRECTORY"),
+		ArchiveRetention: archive.Retention{
+			KeepRounds: p.integer("ARCHIVE_KEEP_ROUNDS", 0),
+			KeepEvery:  p.integer("ARCHIVE_KEEP_EVERY", 0),
+		},
+
+		StatsSummaryInterval: p.duration("STATS_SUMMARY_INTERVAL", 0),
+		RoundSummary:         p.boolean("ROUND_SUMMARY", false),
+
+		TurnDeadline:         p.duration("TURN_DEADLINE", 0),
+		TurnDeadlineWarning:  p.duration("TURN_DEADLINE_WARNING", 12*time.Hour),
+		TurnDeadlineMaxSkips: p.integer("TURN_DEADLINE_MAX_SKIPS", 0),
+	}
+
+	if value := lookup("USER_MAPPINGS"); value == "" {
+		p.problem("USER_MAPPINGS is not set")
+	} else if players, err := userparser.Parse(value); err != nil {
+		p.problem("USER_MAPPINGS is invalid: %v", err)
+	} else {
+		cfg.Players = players
+		if len(players) < 2 {
+			p.problem("USER_MAPPINGS should list at least two players, got %d", len(players))
+		}
+	}
+
+	if cfg.WebhookURL == "" {
+		// Nothing is sent in a dry run, so the webhook isn't needed
+		if !cfg.DryRun {
+			p.problem("DISCORD_WEBHOOK_URL is not set, webhook notifications will fail")
+		}
+	} else if parsed, err := url.Parse(cfg.WebhookURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		p.problem("DISCORD_WEBHOOK_URL is not a valid URL: %s", cfg.WebhookURL)
+	}
+
+	if cfg.PublicKey != "" {
+		if key, err := hex.DecodeString(cfg.PublicKey); err != nil || len(key) != ed25519.PublicKeySize {
+			p.problem("DISCORD_PUBLIC_KEY must be a %d byte hex encoded Ed25519 key", ed25519.PublicKeySize)
+		}
+		if cfg.ApplicationID == "" {
+			p.problem("DISCORD_APPLICATION_ID is required in bot mode")
+		}
+		if cfg.BotToken == "" {
+			p.problem("DISCORD_BOT_TOKEN is required in bot mode")
+		}
+	}
+
+	if value := lookup("DIGEST_SCHEDULE"); value != "" {
+		if parsed, err := schedule.Parse(value); err != nil {
+			p.problem("DIGEST_SCHEDULE is invalid: %v", err)
+		} else {
+			cfg.DigestSchedule = parsed
+		}
+	}
+
+	// A warning that comes before the turn has even started is no use, so warn half way through instead
+	if cfg.TurnDeadline > 0 && cfg.TurnDeadlineWarning >= cfg.TurnDeadline {
+		cfg.TurnDeadlineWarning = cfg.TurnDeadline / 2
+	}
+
+	return cfg, errors.Join(p.problems...)
+}
+
+// parseIgnorePatterns parses comma-separated ignore patterns, lowercased.
+func parseIgnorePatterns(value string) []string {
+	if value == "" {
+		return []string{}
+	}
+	var result []string
+	for _, pattern := range strings.Split(value, ",") {
+		result = append(result, strings.ToLower(strings.TrimSpace(pattern)))
+	}
+	return result
+}
+
+// parseList parses a comma-separated list, leaving out empty entries.
+func parseList(value string) []string {
+	var result []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// parser reads typed variables, collecting a problem for each invalid value.
+type parser struct {
+	lookup   func(string) string
+	problems []error
+}
+
+func (p *parser) problem(format string, args ...any) {
+	p.problems = append(p.problems, fmt.Errorf(format, args...))
+}
+
+// text returns a variable, or fallback if it is not set.
+func (p *parser) text(name, fallback string) string {
+	if value := p.lookup(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// integer parses a non-negative integer variable.
+func (p *parser) integer(name string, fallback int) int {
+	value := p.lookup(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		p.problem("%s must be a non-negative integer, got '%s' (using %d)", name, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// boolean parses a true/false variable.
+func (p *parser) boolean(name string, fallback bool) bool {
+	value := p.lookup(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		p.problem("%s must be true or false, got '%s' (using %t)", name, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// duration parses a non-negative Go duration variable such as "72h".
+func (p *parser) duration(name string, fallback time.Duration) time.Duration {
+	value := p.lookup(name)
+	if value == "" {
+		return fallback
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		p.problem("%s must be a duration such as 30m or 72h, got '%s' (using %v)", name, value, fallback)
+		return fallback
+	}
+	return parsed
+}
+
+// choice parses a variable that must be one of the given (lowercase) options.
+func (p *parser) choice(name, fallback string, options ...string) string {
+	value := strings.ToLower(strings.TrimSpace(p.lookup(name)))
+	if value == "" {
+		return fallback
+	}
+	for _, option := range options {
+		if value == option {
+			return value
+		}
+	}
+	p.problem("%s must be one of %s, got '%s' (using %s)", name, strings.Join(options, ", "), value, fallback)
+	return fallback
+}
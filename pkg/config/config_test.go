@@ -0,0 +1,108 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// valid is the smallest environment that loads without problems.
+var valid = map[string]string{
+	"USER_MAPPINGS":       "1 alice 111,2 bob 222",
+	"DISCORD_WEBHOOK_URL": "https://discord.com/api/webhooks/1/token",
+}
+
+// publicKey is a well-formed Ed25519 public key for the bot mode tests.
+var publicKey = strings.Repeat("ab", 32)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want []string // Text each problem in the error should contain, in order.
+	}{
+		{"valid", nil, nil},
+		{"missing user mappings", map[string]string{"USER_MAPPINGS": ""}, []string{"USER_MAPPINGS is not set"}},
+		{"invalid user mappings", map[string]string{"USER_MAPPINGS": "1 alice"}, []string{"USER_MAPPINGS is invalid"}},
+		{"one player", map[string]string{"USER_MAPPINGS": "1 alice 111"}, []string{"at least two players, got 1"}},
+		{"missing webhook", map[string]string{"DISCORD_WEBHOOK_URL": ""}, []string{"DISCORD_WEBHOOK_URL is not set"}},
+		{"missing webhook in a dry run", map[string]string{"DISCORD_WEBHOOK_URL": "", "DRY_RUN": "true"}, nil},
+		{"relative webhook", map[string]string{"DISCORD_WEBHOOK_URL": "/api/webhooks/1"}, []string{"not a valid URL"}},
+		{"bad integer", map[string]string{"FILE_DEBOUNCE_MS": "-5"}, []string{"FILE_DEBOUNCE_MS must be a non-negative integer, got '-5' (using 30000)"}},
+		{"bad boolean", map[string]string{"DRY_RUN": "sometimes"}, []string{"DRY_RUN must be true or false"}},
+		{"bad duration", map[string]string{"TURN_DEADLINE": "2 days"}, []string{"TURN_DEADLINE must be a duration"}},
+		{"bad choice", map[string]string{"AUTO_RENAME": "move"}, []string{"AUTO_RENAME must be one of off, rename, copy"}},
+		{"bad digest schedule", map[string]string{"DIGEST_SCHEDULE": "0 25 * * *"}, []string{"DIGEST_SCHEDULE is invalid"}},
+		{"bot mode", map[string]string{"DISCORD_PUBLIC_KEY": publicKey, "DISCORD_APPLICATION_ID": "42", "DISCORD_BOT_TOKEN": "token"}, nil},
+		{"bot mode with a short key", map[string]string{"DISCORD_PUBLIC_KEY": "abcd", "DISCORD_APPLICATION_ID": "42", "DISCORD_BOT_TOKEN": "token"},
+			[]string{"DISCORD_PUBLIC_KEY must be a 32 byte hex encoded Ed25519 key"}},
+		{"bot mode without its settings", map[string]string{"DISCORD_PUBLIC_KEY": publicKey},
+			[]string{"DISCORD_APPLICATION_ID is required", "DISCORD_BOT_TOKEN is required"}},
+		{"every problem is reported", map[string]string{"USER_MAPPINGS": "", "FILE_AGE_LIMIT": "soon", "ARCHIVE_KEEP_ROUNDS": "x"},
+			[]string{"FILE_AGE_LIMIT", "ARCHIVE_KEEP_ROUNDS", "USER_MAPPINGS is not set"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, err := Load(lookup(test.env))
+			if cfg == nil {
+				t.Fatal("Load() returned no configuration")
+			}
+			var problems []string
+			if err != nil {
+				problems = strings.Split(err.Error(), "\n")
+			}
+			if len(problems) != len(test.want) {
+				t.Fatalf("Load() error = %v, want %d problems", err, len(test.want))
+			}
+			for i, want := range test.want {
+				if !strings.Contains(problems[i], want) {
+					t.Errorf("problem %d = %q, want one containing %q", i+1, problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		got  func(*Config) any
+		want any
+	}{
+		{"watch directory", nil, func(c *Config) any { return c.WatchDirectory }, "./data"},
+		{"invalid values fall back to the default", map[string]string{"FILE_AGE_LIMIT": "soon"},
+			func(c *Config) any { return c.FileAgeLimit }, 24 * time.Hour},
+		{"choices are case-insensitive", map[string]string{"SAVE_INSPECTION": " Strict "},
+			func(c *Config) any { return c.SaveInspection }, InspectionStrict},
+		{"ignore patterns are lowercased", map[string]string{"IGNORE_PATTERNS": "Backup, .TMP"},
+			func(c *Config) any { return strings.Join(c.IgnorePatterns, "|") }, "backup|.tmp"},
+		{"skip roles leave out empty entries", map[string]string{"DISCORD_SKIP_ROLES": "admin,, mod ,"},
+			func(c *Config) any { return strings.Join(c.SkipRoles, "|") }, "admin|mod"},
+		{"deadline warning within the deadline", map[string]string{"TURN_DEADLINE": "48h"},
+			func(c *Config) any { return c.TurnDeadlineWarning }, 12 * time.Hour},
+		{"deadline warning longer than the deadline", map[string]string{"TURN_DEADLINE": "8h"},
+			func(c *Config) any { return c.TurnDeadlineWarning }, 4 * time.Hour},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cfg, _ := Load(lookup(test.env))
+			if got := test.got(cfg); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+// lookup returns a lookup function for the valid environment with overrides applied. An empty
+// override unsets the variable.
+func lookup(overrides map[string]string) func(string) string {
+	return func(name string) string {
+		if value, ok := overrides[name]; ok {
+			return value
+		}
+		return valid[name]
+	}
+}
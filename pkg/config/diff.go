@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/schedule"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// Change is a setting that differs between two configurations.
+type Change struct {
+	Variable string // Environment variable the setting comes from.
+	Old      string // Previous value, as it would be written in the environment.
+	New      string // New value.
+	Secret   bool   // Whether the values must not be shown, in which case Old and New are empty.
+}
+
+// restartOnly lists the settings that can't be changed while the bot is running. KeepRestartOnly must
+// copy each of them.
+var restartOnly = map[string]bool{
+	"GAME_NAME":              true,
+	"WATCH_DIRECTORY":        true,
+	"STATE_FILE":             true,
+	"HISTORY_FILE":           true,
+	"EVENT_LOG":              true,
+	"ADMIN_AUDIT_LOG":        true,
+	"STATUS_LISTEN_ADDR":     true,
+	"DISCORD_PUBLIC_KEY":     true,
+	"DISCORD_APPLICATION_ID": true,
+	"DISCORD_GUILD_ID":       true,
+	"BOT_LISTEN_ADDR":        true,
+}
+
+// KeepRestartOnly copies the settings that can't be changed while the bot is running from running, so
+// a reloaded configuration carries on with them until the bot is restarted.
+func (c *Config) KeepRestartOnly(running *Config) {
+	c.GameName = running.GameName
+	c.WatchDirectory = running.WatchDirectory
+	c.StateFile = running.StateFile
+	c.HistoryFile = running.HistoryFile
+	c.EventLog = running.EventLog
+	c.AuditLog = running.AuditLog
+	c.StatusListenAddr = running.StatusListenAddr
+	c.PublicKey = running.PublicKey
+	c.ApplicationID = running.ApplicationID
+	c.GuildID = running.GuildID
+	c.BotListenAddr = running.BotListenAddr
+}
+
+// RequiresRestart reports whether the change only takes effect when the bot is restarted.
+func (c Change) RequiresRestart() bool {
+	return RequiresRestart(c.Variable)
+}
+
+// RequiresRestart reports whether a configuration variable can only be changed by restarting the bot.
+func RequiresRestart(variable string) bool {
+	return restartOnly[variable]
+}
+
+// String describes the change, e.g. "FILE_AGE_LIMIT: 24h0m0s → 48h0m0s".
+func (c Change) String() string {
+	if c.Secret {
+		return c.Variable + " changed"
+	}
+	return fmt.Sprintf("%s: %s → %s", c.Variable, orUnset(c.Old), orUnset(c.New))
+}
+
+// orUnset returns value, or "(unset)" if it is empty.
+func orUnset(value string) string {
+	if value == "" {
+		return "(unset)"
+	}
+	return value
+}
+
+// Diff lists the settings that differ between old and new, in the order they are documented.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	add := func(variable, before, after string) {
+		if before != after {
+			changes = append(changes, Change{Variable: variable, Old: before, New: after})
+		}
+	}
+
+	add("GAME_NAME", old.GameName, new.GameName)
+	add("USER_MAPPINGS", formatPlayers(old.Players), formatPlayers(new.Players))
+	add("WATCH_DIRECTORY", old.WatchDirectory, new.WatchDirectory)
+	if old.WebhookURL != new.WebhookURL {
+		changes = append(changes, Change{Variable: "DISCORD_WEBHOOK_URL", Secret: true})
+	}
+	if old.BotToken != new.BotToken {
+		changes = append(changes, Change{Variable: "DISCORD_BOT_TOKEN", Secret: true})
+	}
+	add("DISCORD_API_BASE_URL", old.APIBaseURL, new.APIBaseURL)
+	add("DRY_RUN", strconv.FormatBool(old.DryRun), strconv.FormatBool(new.DryRun))
+	add("DRY_RUN_FILE", old.DryRunFile, new.DryRunFile)
+	add("CONFIG_RELOAD_ANNOUNCE", strconv.FormatBool(old.ReloadAnnounce), strconv.FormatBool(new.ReloadAnnounce))
+
+	add("IGNORE_PATTERNS", strings.Join(old.IgnorePatterns, ","), strings.Join(new.IgnorePatterns, ","))
+	add("FILE_DEBOUNCE_MS", strconv.FormatInt(old.FileDebounce.Milliseconds(), 10), strconv.FormatInt(new.FileDebounce.Milliseconds(), 10))
+	add("FILE_HASH_CHECK", strconv.FormatBool(old.FileHashCheck), strconv.FormatBool(new.FileHashCheck))
+	add("FILE_CHECK_TIME", old.FileCheckTime.String(), new.FileCheckTime.String())
+	add("FILE_AGE_LIMIT", old.FileAgeLimit.String(), new.FileAgeLimit.String())
+	add("SAVE_INSPECTION", old.SaveInspection, new.SaveInspection)
+	add("SAVE_MIN_SIZE_BYTES", strconv.FormatInt(old.SaveMinSize, 10), strconv.FormatInt(new.SaveMinSize, 10))
+	add("AUTO_RENAME", old.AutoRename, new.AutoRename)
+	add("TURN_VALIDATION", strconv.FormatBool(old.TurnValidation), strconv.FormatBool(new.TurnValidation))
+
+	add("STATE_FILE", old.StateFile, new.StateFile)
+	add("HISTORY_FILE", old.HistoryFile, new.HistoryFile)
+	add("EVENT_LOG", old.EventLog, new.EventLog)
+	add("ADMIN_AUDIT_LOG", old.AuditLog, new.AuditLog)
+
+	add("STATUS_LISTEN_ADDR", old.StatusListenAddr, new.StatusListenAddr)
+	if old.AdminToken != new.AdminToken {
+		changes = append(changes, Change{Variable: "ADMIN_TOKEN", Secret: true})
+	}
+
+	add("DISCORD_PUBLIC_KEY", old.PublicKey, new.PublicKey)
+	add("DISCORD_APPLICATION_ID", old.ApplicationID, new.ApplicationID)
+	add("DISCORD_GUILD_ID", old.GuildID, new.GuildID)
+	add("DISCORD_SKIP_ROLES", strings.Join(old.SkipRoles, ","), strings.Join(new.SkipRoles, ","))
+	add("BOT_LISTEN_ADDR", old.BotListenAddr, new.BotListenAddr)
+
+	add("ARCHIVE_DIRECTORY", old.ArchiveDirectory, new.ArchiveDirectory)
+	add("ARCHIVE_KEEP_ROUNDS", strconv.Itoa(old.ArchiveRetention.KeepRounds), strconv.Itoa(new.ArchiveRetention.KeepRounds))
+	add("ARCHIVE_KEEP_EVERY", strconv.Itoa(old.ArchiveRetention.KeepEvery), strconv.Itoa(new.ArchiveRetention.KeepEvery))
+
+	add("STATS_SUMMARY_INTERVAL", formatOptionalDuration(old.StatsSummaryInterval), formatOptionalDuration(new.StatsSummaryInterval))
+	add("ROUND_SUMMARY", strconv.FormatBool(old.RoundSummary), strconv.FormatBool(new.RoundSummary))
+	add("DIGEST_SCHEDULE", formatSchedule(old.DigestSchedule), formatSchedule(new.DigestSchedule))
+
+	add("TURN_DEADLINE", formatOptionalDuration(old.TurnDeadline), formatOptionalDuration(new.TurnDeadline))
+	add("TURN_DEADLINE_WARNING", old.TurnDeadlineWarning.String(), new.TurnDeadlineWarning.String())
+	add("TURN_DEADLINE_MAX_SKIPS", strconv.Itoa(old.TurnDeadlineMaxSkips), strconv.Itoa(new.TurnDeadlineMaxSkips))
+	return changes
+}
+
+// formatPlayers writes players in the USER_MAPPINGS format, leaving out the default preference.
+func formatPlayers(players []userparser.UserMapping) string {
+	parts := make([]string, len(players))
+	for i, player := range players {
+		parts[i] = fmt.Sprintf("%d %s %s", player.Order, player.Username, player.DiscordID)
+		if player.Notify != userparser.NotifyChannel {
+			parts[i] += " " + string(player.Notify)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatOptionalDuration formats a duration where zero means the feature is off.
+func formatOptionalDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.String()
+}
+
+// formatSchedule returns the schedule as written, or nothing if there isn't one.
+func formatSchedule(s *schedule.Schedule) string {
+	if s == nil {
+		return ""
+	}
+	return s.String()
+}
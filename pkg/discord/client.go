@@ -0,0 +1,127 @@
+package discord
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// DefaultAPIBaseURL is the Discord REST API used when DISCORD_API_BASE_URL is not set
+const DefaultAPIBaseURL = "https://discord.com/api/v10"
+
+// Client is a minimal Discord REST API client authenticated with a bot token
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient creates a client for the API at baseURL, or DefaultAPIBaseURL if it is empty,
+// authenticated with token. The base URL can be pointed at a local fake for testing.
+func NewClient(baseURL, token string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultAPIBaseURL
+	}
+	return &Client{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		Token:      token,
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// StatusError is returned when the API answers with a non-2xx status
+type StatusError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s returned status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// do sends a JSON request to the API and decodes the JSON response into out (if non-nil)
+func (c *Client) do(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		reader = bytes.NewReader(jsonBody)
+	}
+
+	req, err := http.NewRequest(method, c.BaseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bot "+c.Token)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusTooManyRequests {
+		metrics.RateLimitHits.Inc("api")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &StatusError{Method: method, Path: path, StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+
+	if out != nil && len(respBody) > 0 {
+		if err := json.Unmarshal(respBody, out); err != nil {
+			return fmt.Errorf("error decoding response from %s %s: %w", method, path, err)
+		}
+	}
+	return nil
+}
+
+// OverwriteCommands replaces the application's slash commands.
+// If guildID is set the commands are registered for that guild only, which takes effect immediately;
+// otherwise they are registered globally.
+func (c *Client) OverwriteCommands(applicationID, guildID string, commands []types.ApplicationCommand) error {
+	path := fmt.Sprintf("/applications/%s/commands", applicationID)
+	if guildID != "" {
+		path = fmt.Sprintf("/applications/%s/guilds/%s/commands", applicationID, guildID)
+	}
+	return c.do(http.MethodPut, path, commands, nil)
+}
+
+// EditOriginalResponse replaces the content of a deferred interaction response
+func (c *Client) EditOriginalResponse(applicationID, interactionToken string, data *types.InteractionResponseData) error {
+	path := fmt.Sprintf("/webhooks/%s/%s/messages/@original", applicationID, interactionToken)
+	return c.do(http.MethodPatch, path, data, nil)
+}
+
+// CreateDMChannel opens (or returns the existing) direct message channel with a user
+func (c *Client) CreateDMChannel(userID string) (string, error) {
+	var channel types.Channel
+	if err := c.do(http.MethodPost, "/users/@me/channels", map[string]string{"recipient_id": userID}, &channel); err != nil {
+		return "", err
+	}
+	if channel.ID == "" {
+		return "", fmt.Errorf("discord returned no channel ID for DM with user %s", userID)
+	}
+	return channel.ID, nil
+}
+
+// CreateMessage posts a message to a channel
+func (c *Client) CreateMessage(channelID string, message *types.Message) error {
+	return c.do(http.MethodPost, fmt.Sprintf("/channels/%s/messages", channelID), message, nil)
+}
@@ -0,0 +1,47 @@
+// Package events defines what is recorded about a game as it happens, such as turns starting and
+// saves being submitted, so that feeds can be generated from it.
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kind is the type of an event.
+type Kind string
+
+// Kinds of event recorded by the monitor.
+const (
+	TurnStarted Kind = "turn_started" // A player was handed the turn.
+	Submitted   Kind = "submitted"    // A player's save arrived.
+	Skipped     Kind = "skipped"      // A player was skipped.
+	Rename      Kind = "rename"       // A misnamed save was found, corrected or renamed automatically.
+	Stall       Kind = "stall"        // A turn has been waiting too long, or missed its deadline.
+	Paused      Kind = "paused"       // The game was paused.
+	Resumed     Kind = "resumed"      // The game was resumed.
+)
+
+// Event is a single thing that happened in a game.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Game   string    `json:"game"`
+	Kind   Kind      `json:"kind"`
+	Player string    `json:"player,omitempty"` // The player the event is about, if any.
+	Turn   int       `json:"turn,omitempty"`   // The turn the event is about, if any.
+	Title  string    `json:"title"`            // One-line description of the event.
+	Detail string    `json:"detail,omitempty"` // Further details.
+}
+
+// ID returns an identifier for the event that stays the same every time it is read back.
+func (e Event) ID() string {
+	return fmt.Sprintf("%s-%s-%d", strings.ToLower(e.Game), e.Kind, e.Time.UnixNano())
+}
+
+// ForGame returns a filter for events of the named game (case-insensitive), for reading them back
+// from a jsonl.Log.
+func ForGame(game string) func(Event) bool {
+	return func(event Event) bool {
+		return strings.EqualFold(event.Game, game)
+	}
+}
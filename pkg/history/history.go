@@ -0,0 +1,141 @@
+// Package history defines the completed turns recorded in the turn history, and computes per-player
+// statistics from them.
+package history
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Turn is a completed turn: a player was notified and later handed the game on.
+type Turn struct {
+	Game          string        `json:"game"`
+	Turn          int           `json:"turn"`
+	Player        string        `json:"player"`
+	NotifiedAt    time.Time     `json:"notified_at"`              // When the player was told it was their turn.
+	SubmittedAt   time.Time     `json:"submitted_at"`             // When their save appeared (or they were skipped).
+	ReceivedFile  string        `json:"received_file,omitempty"`  // Save that handed them the turn, empty after a skip.
+	SubmittedFile string        `json:"submitted_file,omitempty"` // Save they made, empty if they were skipped.
+	Paused        time.Duration `json:"paused,omitempty"`         // Time the game was paused during the turn.
+	Reminders     int           `json:"reminders,omitempty"`      // Reminders sent before the turn was done.
+	Skipped       bool          `json:"skipped,omitempty"`        // True if the turn ended with the player being skipped.
+}
+
+// Duration returns how long the player took, not counting time the game was paused.
+func (t Turn) Duration() time.Duration {
+	return t.SubmittedAt.Sub(t.NotifiedAt) - t.Paused
+}
+
+// ForGame returns a filter for turns of the named game (case-insensitive), for reading them back
+// from a jsonl.Log.
+func ForGame(game string) func(Turn) bool {
+	return func(turn Turn) bool {
+		return strings.EqualFold(turn.Game, game)
+	}
+}
+
+// PlayerStats summarises a player's completed turns. Skipped turns count towards Skips and
+// Reminders but not towards the turn times.
+type PlayerStats struct {
+	Player    string
+	Turns     int // Turns the player completed with a save.
+	Skips     int // Turns that ended with the player being skipped.
+	Reminders int // Reminders sent across all their turns.
+	Average   time.Duration
+	Median    time.Duration
+	Longest   time.Duration
+}
+
+// Stats computes statistics for each of the given players, in the order given, from turns
+// submitted at or after since (zero for all turns).
+func Stats(turns []Turn, players []string, since time.Time) []PlayerStats {
+	durations := make(map[string][]time.Duration)
+	stats := make([]PlayerStats, len(players))
+	index := make(map[string]int)
+	for i, player := range players {
+		stats[i].Player = player
+		index[strings.ToLower(player)] = i
+	}
+
+	for _, turn := range turns {
+		i, ok := index[strings.ToLower(turn.Player)]
+		if !ok || turn.SubmittedAt.Before(since) {
+			continue
+		}
+		stats[i].Reminders += turn.Reminders
+		if turn.Skipped {
+			stats[i].Skips++
+			continue
+		}
+		stats[i].Turns++
+		durations[players[i]] = append(durations[players[i]], turn.Duration())
+	}
+
+	for i := range stats {
+		times := durations[stats[i].Player]
+		if len(times) == 0 {
+			continue
+		}
+		slices.Sort(times)
+
+		var total time.Duration
+		for _, d := range times {
+			total += d
+		}
+		stats[i].Average = total / time.Duration(len(times))
+		stats[i].Longest = times[len(times)-1]
+		if mid := len(times) / 2; len(times)%2 == 1 {
+			stats[i].Median = times[mid]
+		} else {
+			stats[i].Median = (times[mid-1] + times[mid]) / 2
+		}
+	}
+	return stats
+}
+
+// Slowest returns the player with the longest average turn time, if any player completed a turn.
+func Slowest(stats []PlayerStats) (PlayerStats, bool) {
+	var slowest PlayerStats
+	found := false
+	for _, s := range stats {
+		if s.Turns > 0 && (!found || s.Average > slowest.Average) {
+			slowest, found = s, true
+		}
+	}
+	return slowest, found
+}
+
+// Round returns the turns played in a round, one per player in the order they were played. If a
+// player's turn was recorded more than once (after a rollback), only the latest is kept.
+func Round(turns []Turn, round int) []Turn {
+	var played []Turn
+	seen := make(map[string]bool)
+	for i := len(turns) - 1; i >= 0; i-- {
+		key := strings.ToLower(turns[i].Player)
+		if turns[i].Turn != round || seen[key] {
+			continue
+		}
+		seen[key] = true
+		played = append(played, turns[i])
+	}
+	slices.Reverse(played)
+	return played
+}
+
+// Span returns the time from the first notification to the last submission across turns.
+func Span(turns []Turn) time.Duration {
+	if len(turns) == 0 {
+		return 0
+	}
+	start, end := turns[0].NotifiedAt, turns[0].SubmittedAt
+	for _, turn := range turns[1:] {
+		if turn.NotifiedAt.Before(start) {
+			start = turn.NotifiedAt
+		}
+		if turn.SubmittedAt.After(end) {
+			end = turn.SubmittedAt
+		}
+	}
+	return end.Sub(start)
+}
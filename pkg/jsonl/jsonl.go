@@ -0,0 +1,89 @@
+// Package jsonl appends records to JSON Lines files, one JSON object per line, and reads them back.
+package jsonl
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Log is an append-only JSON Lines file of records of type T. It is safe for concurrent use.
+type Log[T any] struct {
+	path string
+	mu   sync.Mutex
+}
+
+// New creates a log for the file at path. The file and its directory are created on the first Append.
+func New[T any](path string) *Log[T] {
+	return &Log[T]{path: path}
+}
+
+// Path returns the location of the file.
+func (l *Log[T]) Path() string {
+	return l.path
+}
+
+// Append adds a record to the end of the file.
+func (l *Log[T]) Append(record T) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error marshaling record for %s: %w", l.path, err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if dir := filepath.Dir(l.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", l.path, err)
+		}
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %w", l.path, err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing %s: %w", l.path, err)
+	}
+	return f.Close()
+}
+
+// Read returns the records for which keep returns true, or every record if keep is nil, oldest
+// first. Blank lines are skipped. It returns no records if the file does not exist yet.
+func (l *Log[T]) Read(keep func(T) bool) ([]T, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s: %w", l.path, err)
+	}
+	defer f.Close()
+
+	var records []T
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record T
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("error parsing %s line %d: %w", l.path, line, err)
+		}
+		if keep == nil || keep(record) {
+			records = append(records, record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", l.path, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,164 @@
+// Package metrics keeps the bot's counters and gauges and writes them in the Prometheus text
+// exposition format, without depending on the Prometheus client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The bot's metrics. Label values are passed in the order the labels are listed here.
+var (
+	SavesProcessed = NewCounter("pbem_saves_processed_total",
+		"Stable saves processed, by game and result (handed_over, rejected or out_of_order).", "game", "result")
+	NotificationsSent = NewCounter("pbem_notifications_sent_total",
+		"Notifications delivered to Discord, by backend (webhook or dm) and HTTP status code.", "backend", "status")
+	NotificationsFailed = NewCounter("pbem_notifications_failed_total",
+		"Notifications that could not be delivered after retrying, by backend and last HTTP status code (or error).", "backend", "status")
+	RateLimitHits = NewCounter("pbem_discord_rate_limit_hits_total",
+		"Responses from Discord with status 429, by backend.", "backend")
+	CurrentTurn = NewGauge("pbem_current_turn",
+		"Turn currently being played, by game.", "game")
+	TurnWaitingSeconds = NewGauge("pbem_turn_waiting_seconds",
+		"Seconds since the current player was notified, by game.", "game")
+	Polls = NewCounter("pbem_polls_total",
+		"Polls of the watch directory, by result (ok or error).", "result")
+	PollDuration = NewGauge("pbem_poll_duration_seconds",
+		"How long the most recent poll of the watch directory took.")
+)
+
+// registry holds every metric in the order it was created.
+var (
+	registryMu sync.Mutex
+	registry   []*family
+)
+
+// family is a metric with a set of labelled values.
+type family struct {
+	name   string
+	help   string
+	kind   string // counter or gauge
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64 // Keyed by the joined label values.
+}
+
+// newFamily creates and registers a metric.
+func newFamily(name, help, kind string, labels []string) *family {
+	f := &family{name: name, help: help, kind: kind, labels: labels, values: make(map[string]float64)}
+	registryMu.Lock()
+	registry = append(registry, f)
+	registryMu.Unlock()
+	return f
+}
+
+// key joins label values into a map key, panicking if the count doesn't match the labels.
+func (f *family) key(labelValues []string) string {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", f.name, len(f.labels), len(labelValues)))
+	}
+	return strings.Join(labelValues, "\xff")
+}
+
+func (f *family) add(delta float64, labelValues []string) {
+	key := f.key(labelValues)
+	f.mu.Lock()
+	f.values[key] += delta
+	f.mu.Unlock()
+}
+
+func (f *family) set(value float64, labelValues []string) {
+	key := f.key(labelValues)
+	f.mu.Lock()
+	f.values[key] = value
+	f.mu.Unlock()
+}
+
+// write writes the metric's help, type and samples, sorted by label values.
+func (f *family) write(w io.Writer) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, escapeHelp(f.help), f.name, f.kind); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(f.values))
+	for key := range f.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		labels := ""
+		if len(f.labels) > 0 {
+			pairs := make([]string, len(f.labels))
+			for i, value := range strings.Split(key, "\xff") {
+				pairs[i] = fmt.Sprintf("%s=\"%s\"", f.labels[i], escapeLabel(value))
+			}
+			labels = "{" + strings.Join(pairs, ",") + "}"
+		}
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, labels, strconv.FormatFloat(f.values[key], 'g', -1, 64)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter is a value that only goes up.
+type Counter struct{ f *family }
+
+// NewCounter creates and registers a counter with the given label names.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{newFamily(name, help, "counter", labels)}
+}
+
+// Inc adds one to the counter for the given label values.
+func (c *Counter) Inc(labelValues ...string) {
+	c.f.add(1, labelValues)
+}
+
+// Gauge is a value that can go up and down.
+type Gauge struct{ f *family }
+
+// NewGauge creates and registers a gauge with the given label names.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{newFamily(name, help, "gauge", labels)}
+}
+
+// Set sets the gauge for the given label values.
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.f.set(value, labelValues)
+}
+
+// Write writes every registered metric in the Prometheus text exposition format.
+func Write(w io.Writer) error {
+	registryMu.Lock()
+	families := append([]*family(nil), registry...)
+	registryMu.Unlock()
+
+	for _, f := range families {
+		if err := f.write(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// escapeHelp escapes backslashes and newlines in help text.
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// escapeLabel escapes backslashes, quotes and newlines in a label value.
+func escapeLabel(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
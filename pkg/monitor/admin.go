@@ -0,0 +1,90 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// ErrUnknownPlayer is returned by actions given a username that is not in USER_MAPPINGS.
+var ErrUnknownPlayer = errors.New("unknown player")
+
+// ErrTooFewPlayers is returned when sitting a player out would leave fewer than two players in the rotation.
+var ErrTooFewPlayers = errors.New("at least two players must remain in the rotation")
+
+// lookupPlayer returns the index and mapping of a player by username (case-insensitive).
+func (g *GameState) lookupPlayer(username string) (int, userparser.UserMapping, error) {
+	index := indexOfPlayer(g.players, username)
+	if index == -1 {
+		return -1, userparser.UserMapping{}, fmt.Errorf("%w: %s", ErrUnknownPlayer, username)
+	}
+	return index, g.players[index], nil
+}
+
+// SetTurn makes it the given player's turn to play turn, and sends them the usual turn notification.
+// If the state changed but the notification failed, the player is returned along with the error.
+func (g *GameState) SetTurn(turn int, username string) (userparser.UserMapping, error) {
+	if turn < 1 {
+		return userparser.UserMapping{}, fmt.Errorf("turn must be at least 1, got %d", turn)
+	}
+	index, target, err := g.lookupPlayer(username)
+	if err != nil {
+		return target, err
+	}
+
+	g.beginTurn(turn, index, "", false)
+	fmt.Printf("🛠️ Turn set to %d, %s is up\n", turn, target.Username)
+
+	nextIndex, saveTurn := g.nextPlayer(index, turn)
+	return target, webhook.SendWebHook(target, g.players[nextIndex].Username, saveTurn)
+}
+
+// SetSittingOut takes a player out of the rotation (out is true) or puts them back in. Players
+// sitting out are passed over when working out who is next. It reports whether anything changed.
+func (g *GameState) SetSittingOut(username string, out bool) (userparser.UserMapping, bool, error) {
+	_, target, err := g.lookupPlayer(username)
+	if err != nil {
+		return target, false, err
+	}
+	key := strings.ToLower(target.Username)
+
+	g.mu.Lock()
+	if g.sittingOut[key] == out {
+		g.mu.Unlock()
+		return target, false, nil
+	}
+	if out && len(g.players)-len(g.sittingOut) <= 2 {
+		g.mu.Unlock()
+		return target, false, ErrTooFewPlayers
+	}
+	if out {
+		g.sittingOut[key] = true
+	} else {
+		delete(g.sittingOut, key)
+	}
+	g.mu.Unlock()
+	g.persist()
+
+	if out {
+		fmt.Printf("🪑 %s is sitting out and will be passed over\n", target.Username)
+	} else {
+		fmt.Printf("🪑 %s has been reinstated in the turn order\n", target.Username)
+	}
+	return target, true, nil
+}
+
+// ResendTurnNotification sends the current player their turn notification again.
+func (g *GameState) ResendTurnNotification() (userparser.UserMapping, error) {
+	snapshot := g.Snapshot()
+	current, ok := snapshot.CurrentMapping()
+	if !ok {
+		return current, ErrNoCurrentPlayer
+	}
+
+	nextIndex, saveTurn := snapshot.NextPlayer(snapshot.CurrentPlayer, snapshot.CurrentTurn)
+	fmt.Printf("🔁 Resending turn %d notification to %s\n", snapshot.CurrentTurn, current.Username)
+	return current, webhook.SendWebHook(current, snapshot.Players[nextIndex].Username, saveTurn)
+}
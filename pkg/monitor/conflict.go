@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// conflictPatterns match the names sync tools give to conflict copies of a file.
+// The first capture group is the original name without extension, the last is the extension (if any).
+var conflictPatterns = []*regexp.Regexp{
+	// Dropbox: "pbem1_turn3_bob (alice's conflicted copy 2026-01-02).se"
+	regexp.MustCompile(`^(.+?) \([^()]*conflicted copy[^()]*\)(\.[^.]+)?$`),
+	// Syncthing: "pbem1_turn3_bob.sync-conflict-20260102-153045-ABCDEFG.se"
+	regexp.MustCompile(`^(.+?)\.sync-conflict-\d{8}-\d{6}(?:-[a-z0-9]+)?(\.[^.]+)?$`),
+	// Google Drive and desktop duplicates: "pbem1_turn3_bob (1).se"
+	regexp.MustCompile(`^(.+?) \(\d+\)(\.[^.]+)?$`),
+}
+
+// isTemporarySyncFile reports whether a (lowercase) filename is a partial or temporary file written
+// by a sync tool while it is still transferring, which should never be treated as a save.
+func isTemporarySyncFile(filename string) bool {
+	switch {
+	case strings.HasPrefix(filename, ".syncthing.") && strings.HasSuffix(filename, ".tmp"):
+		return true // Syncthing partial download
+	case strings.HasPrefix(filename, "~syncthing~") && strings.HasSuffix(filename, ".tmp"):
+		return true // Syncthing partial download on Windows
+	case strings.HasPrefix(filename, "~$"):
+		return true // Office-style lock and owner files
+	case strings.HasPrefix(filename, ".dropbox.cache"):
+		return true // Dropbox cache
+	}
+	return false
+}
+
+// conflictCopyOriginal returns the name of the file a (lowercase) conflict copy was made from.
+// The second return value is false if the filename is not a conflict copy.
+func conflictCopyOriginal(filename string) (string, bool) {
+	for _, pattern := range conflictPatterns {
+		if matches := pattern.FindStringSubmatch(filename); matches != nil {
+			return matches[1] + matches[2], true
+		}
+	}
+	return "", false
+}
+
+// looksLikeSave reports whether a (lowercase) filename is named like one of this game's saves.
+func looksLikeSave(filename, gameName string, userMappings []userparser.UserMapping) bool {
+	if strings.HasPrefix(filename, gameName) {
+		return true
+	}
+	for _, mapping := range userMappings {
+		if strings.Contains(filename, strings.ToLower(mapping.Username)) {
+			return true
+		}
+	}
+	return false
+}
+
+// handleConflictCopy tracks a conflict copy of a save and, once its content has been stable for the
+// debounce period, alerts the game instead of processing it as a new save. A copy that changes after
+// the alert is reported again once it settles.
+func handleConflictCopy(dirPath string, file fs.DirEntry, original string, fileTracker map[string]*FileTrackingInfo, now int64, config *settings) {
+	filename := file.Name()
+	info, exists := fileTracker[filename]
+	if !exists {
+		fmt.Printf("⚔️ Sync conflict copy detected: %s (of %s), starting debounce period\n", filename, original)
+		fileTracker[filename] = newFileTrackingInfo(file, now)
+		return
+	}
+
+	updateStability(config.fs, info, filename, filepath.Join(dirPath, filename), file, now, config.hashFiles)
+	if info.Processed || (now-info.StableSince) < int64(config.fileDebounceMs) {
+		return
+	}
+
+	fmt.Printf("⚔️ Alerting game about sync conflict copy %s of %s\n", filename, original)
+	if err := webhook.SendConflictWebHook(filename, original); err != nil {
+		fmt.Printf("❌ Failed to send conflict alert for %s: %v\n", filename, err)
+	}
+	info.Processed = true
+}
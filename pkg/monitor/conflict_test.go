@@ -0,0 +1,51 @@
+package monitor
+
+import "testing"
+
+func TestConflictCopyOriginal(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		want     string // The original name, or empty if the file isn't a conflict copy.
+	}{
+		{"dropbox", "pbem1_turn3_bob (alice's conflicted copy 2026-01-02).se", "pbem1_turn3_bob.se"},
+		{"dropbox without an extension", "pbem1_turn3_bob (alice's conflicted copy 2026-01-02)", "pbem1_turn3_bob"},
+		{"syncthing", "pbem1_turn3_bob.sync-conflict-20260102-153045-abcdefg.se", "pbem1_turn3_bob.se"},
+		{"syncthing without a device id", "pbem1_turn3_bob.sync-conflict-20260102-153045.se", "pbem1_turn3_bob.se"},
+		{"numbered duplicate", "pbem1_turn3_bob (1).se", "pbem1_turn3_bob.se"},
+		{"numbered duplicate with spaces in the name", "my game turn3 (12).se", "my game turn3.se"},
+		{"ordinary save", "pbem1_turn3_bob.se", ""},
+		{"parentheses in a save name", "pbem1_turn3_bob (final).se", ""},
+		{"sync-conflict without a timestamp", "pbem1_turn3_bob.sync-conflict.se", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := conflictCopyOriginal(test.filename)
+			if ok != (test.want != "") || got != test.want {
+				t.Errorf("conflictCopyOriginal(%q) = %q, %t, want %q", test.filename, got, ok, test.want)
+			}
+		})
+	}
+}
+
+func TestIsTemporarySyncFile(t *testing.T) {
+	tests := []struct {
+		filename string
+		want     bool
+	}{
+		{".syncthing.pbem1_turn3_bob.se.tmp", true},
+		{"~syncthing~pbem1_turn3_bob.se.tmp", true},
+		{"~$pbem1_turn3_bob.se", true},
+		{".dropbox.cache", true},
+		{"pbem1_turn3_bob.se", false},
+		{".syncthing.pbem1_turn3_bob.se", false},
+		{"pbem1_turn3_bob.se.tmp", false},
+	}
+
+	for _, test := range tests {
+		if got := isTemporarySyncFile(test.filename); got != test.want {
+			t.Errorf("isTemporarySyncFile(%q) = %t, want %t", test.filename, got, test.want)
+		}
+	}
+}
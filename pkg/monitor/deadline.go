@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// deadlinePolicy is how long a player has to take their turn before they are skipped.
+type deadlinePolicy struct {
+	limit    time.Duration // Time allowed for a turn, zero for no deadline.
+	warning  time.Duration // How long before the deadline the final warning is sent.
+	maxSkips int           // Skips a player may have before they are no longer skipped automatically, zero for no limit.
+}
+
+// deadlineFromConfig returns the deadline policy set by cfg.
+func deadlineFromConfig(cfg *config.Config) deadlinePolicy {
+	if cfg.TurnDeadline <= 0 {
+		return deadlinePolicy{}
+	}
+	return deadlinePolicy{
+		limit:    cfg.TurnDeadline,
+		warning:  cfg.TurnDeadlineWarning,
+		maxSkips: cfg.TurnDeadlineMaxSkips,
+	}
+}
+
+// setDeadlinePolicy sets the deadline enforced on turns.
+func (g *GameState) setDeadlinePolicy(policy deadlinePolicy) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.deadline = policy
+}
+
+// enforceDeadline sends the current player a final warning as the deadline approaches, and skips
+// them once it has passed. Time the game spends paused doesn't count towards the deadline. A player
+// who already has the maximum number of skips keeps the turn, and the game channel is told instead.
+func (g *GameState) enforceDeadline() {
+	snapshot := g.Snapshot()
+	current, ok := snapshot.CurrentMapping()
+	if !ok || snapshot.Paused || snapshot.Deadline <= 0 {
+		return
+	}
+
+	g.mu.RLock()
+	policy, warned, missed := g.deadline, g.deadlineWarned, g.deadlineMissed
+	g.mu.RUnlock()
+
+	remaining := policy.limit - snapshot.Waiting(g.now())
+	if remaining > 0 {
+		if warned || remaining > policy.warning {
+			return
+		}
+		g.mu.Lock()
+		g.deadlineWarned = true
+		g.mu.Unlock()
+		g.persist()
+
+		g.recordEvent(events.Stall, current.Username, snapshot.CurrentTurn,
+			fmt.Sprintf("Final warning to %s", current.Username),
+			fmt.Sprintf("%s left before turn %d is skipped.", remaining.Round(time.Minute), snapshot.CurrentTurn))
+		fmt.Printf("⌛ Final warning to %s: %s left to play turn %d\n", current.Username, remaining.Round(time.Minute), snapshot.CurrentTurn)
+		if err := webhook.SendDeadlineWarningWebHook(current, snapshot.CurrentTurn, remaining); err != nil {
+			fmt.Printf("❌ Failed to send the deadline warning: %v\n", err)
+		}
+		return
+	}
+	if missed {
+		return
+	}
+
+	g.mu.Lock()
+	g.deadlineMissed = true
+	g.mu.Unlock()
+	g.persist()
+
+	if policy.maxSkips > 0 {
+		if skips := g.skipCount(current.Username); skips >= policy.maxSkips {
+			fmt.Printf("⌛ %s missed the turn %d deadline but has already been skipped %d times, leaving the turn with them\n",
+				current.Username, snapshot.CurrentTurn, skips)
+			g.recordEvent(events.Stall, current.Username, snapshot.CurrentTurn,
+				fmt.Sprintf("%s missed the deadline", current.Username),
+				fmt.Sprintf("They have already been skipped %d times, so the turn stays with them.", skips))
+			if err := webhook.SendDeadlineSkipLimitWebHook(current, snapshot.CurrentTurn, skips); err != nil {
+				fmt.Printf("❌ Failed to announce the skip limit: %v\n", err)
+			}
+			return
+		}
+	}
+
+	fmt.Printf("⌛ %s missed the %s deadline for turn %d, skipping them\n", current.Username, policy.limit, snapshot.CurrentTurn)
+	if err := webhook.SendDeadlineMissedWebHook(current, snapshot.CurrentTurn, policy.limit); err != nil {
+		fmt.Printf("❌ Failed to announce the missed deadline: %v\n", err)
+	}
+	if _, _, err := g.SkipCurrentPlayer(); err != nil {
+		fmt.Printf("❌ Failed to skip %s: %v\n", current.Username, err)
+	}
+}
+
+// skipCount returns how many times the player has been skipped. The count is kept in the state file,
+// so it doesn't depend on the turn history being kept.
+func (g *GameState) skipCount(username string) int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.skips[strings.ToLower(username)]
+}
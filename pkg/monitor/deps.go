@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/jsonl"
+)
+
+// FileSystem is the access to the watch directory the monitor needs. OSFileSystem is used unless
+// another is injected, such as the in-memory directory of a simulation.
+type FileSystem interface {
+	ReadDir(name string) ([]fs.DirEntry, error)
+	Stat(name string) (fs.FileInfo, error)
+	Open(name string) (fs.File, error)
+	Rename(oldPath, newPath string) error
+	WriteFile(name string, data []byte) error
+	Remove(name string) error
+}
+
+// OSFileSystem is the real filesystem.
+type OSFileSystem struct{}
+
+func (OSFileSystem) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (OSFileSystem) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+func (OSFileSystem) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (OSFileSystem) Rename(oldPath, newPath string) error       { return os.Rename(oldPath, newPath) }
+func (OSFileSystem) WriteFile(name string, data []byte) error   { return os.WriteFile(name, data, 0o644) }
+func (OSFileSystem) Remove(name string) error                   { return os.Remove(name) }
+
+// Clock tells the monitor the time. SystemClock is used unless another is injected, such as the
+// manually advanced clock of a simulation.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the real time.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time { return time.Now() }
+
+// SetClock replaces the clock the game state uses for turn timing, pauses, deadlines and summaries.
+func (g *GameState) SetClock(clock Clock) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.clock = clock
+}
+
+// now returns the current time according to the game's clock. Callers must not hold the lock.
+func (g *GameState) now() time.Time {
+	g.mu.RLock()
+	clock := g.clock
+	g.mu.RUnlock()
+	return nowFrom(clock)
+}
+
+// nowFrom returns the time from clock, or the system time if there isn't one.
+func nowFrom(clock Clock) time.Time {
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
+
+// SetLogs replaces where completed turns and game events are recorded, and reloads the turn history.
+// Either may be nil to record nothing.
+func (g *GameState) SetLogs(turnLog *jsonl.Log[history.Turn], eventLog *jsonl.Log[events.Event]) error {
+	g.mu.Lock()
+	g.turnLog = turnLog
+	g.eventLog = eventLog
+	g.mu.Unlock()
+	if turnLog == nil {
+		return nil
+	}
+	return g.loadHistory()
+}
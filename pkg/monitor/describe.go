@@ -0,0 +1,79 @@
+package monitor
+
+import "strings"
+
+// FileReport describes how the monitor would treat a file in the watch directory, given the
+// current game state.
+type FileReport struct {
+	Name         string
+	Temporary    bool   // A sync tool's partial or temporary file, never processed.
+	ConflictOf   string // The save this is a conflict copy of, empty if it isn't one.
+	Ignored      bool   // Matches one of the ignore patterns.
+	GameMatches  bool   // Starts with the configured game name.
+	RenameTo     string // The name a misnamed save should have, if it can be worked out.
+	Player       string // Player the save hands the turn to, empty if no player matched.
+	ClaimedTurn  int    // Turn number in the filename, zero if it doesn't have one.
+	Turn         int    // Turn the save would hand over.
+	NextPlayer   string // Player who would be asked to save next.
+	NextTurn     int    // Turn the next save would hand over.
+	OutOfOrder   bool   // Whether the save doesn't fit the expected turn order.
+	Problem      string // Why the turn wouldn't be handed over, empty if it would.
+	ExpectedName string // Name of the save the game is waiting for, if a turn is in progress.
+}
+
+// DescribeFile works out how the monitor would treat a file called name, following the same steps
+// as a poll of the watch directory. Nothing is changed and no one is notified.
+func (g *GameState) DescribeFile(name string, ignorePatterns []string) FileReport {
+	filename := strings.ToLower(name)
+	snapshot := g.Snapshot()
+	report := FileReport{Name: name, ClaimedTurn: extractTurnNumber(filename)}
+
+	if _, ok := snapshot.CurrentMapping(); ok {
+		nextIndex, nextTurn := snapshot.NextPlayer(snapshot.CurrentPlayer, snapshot.CurrentTurn)
+		report.ExpectedName = g.saveFileName(nextTurn, snapshot.Players[nextIndex].Username)
+	}
+
+	if isTemporarySyncFile(filename) {
+		report.Temporary = true
+		report.Problem = "temporary file written by a sync tool"
+		return report
+	}
+	if original, isConflict := conflictCopyOriginal(filename); isConflict && looksLikeSave(original, snapshot.GameName, snapshot.Players) {
+		report.ConflictOf = original
+		report.Problem = "conflict copy of " + original + ", the game channel would be alerted"
+		return report
+	}
+	if shouldIgnoreFile(filename, ignorePatterns) {
+		report.Ignored = true
+		report.Problem = "matches an ignore pattern"
+		return report
+	}
+
+	report.GameMatches = strings.HasPrefix(filename, snapshot.GameName)
+	if !report.GameMatches {
+		if _, expected, ok := expectedRename(name, g); ok {
+			report.RenameTo = expected
+		}
+		report.Problem = "doesn't start with the game name '" + snapshot.GameName + "'"
+		return report
+	}
+
+	playerIndex := indexOfPlayerInFilename(snapshot.Players, filename)
+	if playerIndex == -1 {
+		report.Problem = "no player's name is in the filename"
+		return report
+	}
+	report.Player = snapshot.Players[playerIndex].Username
+
+	sequence := checkSequence(snapshot, name, report.ClaimedTurn, playerIndex)
+	report.Turn = sequence.Turn
+	if sequence.Problem != sequenceOK {
+		report.OutOfOrder = true
+		report.Problem = sequence.Description
+	}
+
+	nextIndex, nextTurn := snapshot.NextPlayer(playerIndex, sequence.Turn)
+	report.NextPlayer = snapshot.Players[nextIndex].Username
+	report.NextTurn = nextTurn
+	return report
+}
@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/schedule"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// sendRoundSummaryIfDue posts a summary of the last round once every player has played it, which
+// is when the current turn moves past it. Each round is only summarised once, even after a rollback.
+func (g *GameState) sendRoundSummaryIfDue() {
+	if g.turnLog == nil {
+		return
+	}
+
+	g.mu.RLock()
+	round := g.currentTurn - 1
+	due := g.currentPlayer >= 0 && round >= 1 && round > g.lastRoundSummary
+	g.mu.RUnlock()
+	if !due {
+		return
+	}
+
+	// Mark the round first, so a failure doesn't repeat the summary on every poll
+	g.mu.Lock()
+	g.lastRoundSummary = round
+	g.mu.Unlock()
+	g.persist()
+
+	turns, err := g.TurnHistory()
+	if err != nil {
+		fmt.Printf("❌ Failed to read turn history for the round %d summary: %v\n", round, err)
+		return
+	}
+	played := history.Round(turns, round)
+	if len(played) == 0 {
+		fmt.Printf("ℹ️ No turns recorded for round %d, not posting a summary\n", round)
+		return
+	}
+
+	fmt.Printf("🏁 Round %d complete in %s, posting the summary\n", round, history.Span(played).Round(time.Minute))
+	if err := webhook.SendRoundSummaryWebHook(round, played); err != nil {
+		fmt.Printf("❌ Failed to post the round %d summary: %v\n", round, err)
+	}
+}
+
+// sendDigestIfDue posts a digest of the turns played since the last one whenever the schedule
+// fires. If the bot was down when it should have fired, the digest is posted when it next polls.
+func (g *GameState) sendDigestIfDue(digestSchedule *schedule.Schedule) {
+	if digestSchedule == nil || g.turnLog == nil {
+		return
+	}
+
+	now := g.now()
+	g.mu.Lock()
+	since := g.lastDigest
+	if since.IsZero() {
+		g.lastDigest = now
+	}
+	g.mu.Unlock()
+	if since.IsZero() {
+		g.persist()
+		return
+	}
+	if next := digestSchedule.Next(since); next.IsZero() || now.Before(next) {
+		return
+	}
+
+	g.mu.Lock()
+	g.lastDigest = now
+	g.mu.Unlock()
+	g.persist()
+
+	turns, err := g.TurnHistory()
+	if err != nil {
+		fmt.Printf("❌ Failed to read turn history for the digest: %v\n", err)
+		return
+	}
+	var recent []history.Turn
+	for _, turn := range turns {
+		if !turn.SubmittedAt.Before(since) {
+			recent = append(recent, turn)
+		}
+	}
+
+	snapshot := g.Snapshot()
+	var players []string
+	for _, player := range snapshot.Players {
+		players = append(players, player.Username)
+	}
+	var current string
+	if mapping, ok := snapshot.CurrentMapping(); ok {
+		current = mapping.Username
+	}
+
+	fmt.Printf("📰 Posting the digest of %d turns since %s\n", len(recent), since.Format(time.DateTime))
+	err = webhook.SendDigestWebHook(since, recent, history.Stats(recent, players, since),
+		snapshot.CurrentTurn, current, snapshot.Waiting(now))
+	if err != nil {
+		fmt.Printf("❌ Failed to post the digest: %v\n", err)
+	}
+}
@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+)
+
+// ErrNoEventLog is returned when events are asked for but none are being recorded.
+var ErrNoEventLog = errors.New("EVENT_LOG is not set, so no game events are recorded")
+
+// recordEvent appends an event about the game to the event log, if there is one.
+func (g *GameState) recordEvent(kind events.Kind, player string, turn int, title, detail string) {
+	if g.eventLog == nil {
+		return
+	}
+	err := g.eventLog.Append(events.Event{
+		Time:   g.now().UTC(),
+		Game:   g.GameName(),
+		Kind:   kind,
+		Player: player,
+		Turn:   turn,
+		Title:  title,
+		Detail: detail,
+	})
+	if err != nil {
+		fmt.Printf("❌ Failed to record %s event: %v\n", kind, err)
+	}
+}
+
+// recordHandOver records the events for a turn passing to the player at playerIndex: the previous
+// player submitting their save or being skipped (if completed is set), then the new turn starting.
+func (g *GameState) recordHandOver(completed *history.Turn, turn, playerIndex int, saveFile string) {
+	if completed != nil {
+		if completed.Skipped {
+			g.recordEvent(events.Skipped, completed.Player, completed.Turn,
+				fmt.Sprintf("%s was skipped on turn %d", completed.Player, completed.Turn),
+				fmt.Sprintf("The turn had been waiting on them for %s.", completed.Duration().Round(time.Minute)))
+		} else {
+			g.recordEvent(events.Submitted, completed.Player, completed.Turn,
+				fmt.Sprintf("%s submitted turn %d", completed.Player, completed.Turn),
+				fmt.Sprintf("Saved as %s after %s.", completed.SubmittedFile, completed.Duration().Round(time.Minute)))
+		}
+	}
+
+	player := g.Players()[playerIndex].Username
+	detail := "Continue from the latest save."
+	if saveFile != "" {
+		detail = fmt.Sprintf("Load %s.", saveFile)
+	}
+	g.recordEvent(events.TurnStarted, player, turn, fmt.Sprintf("Turn %d: it's %s's turn", turn, player), detail)
+}
+
+// RecentEvents returns up to count of the game's most recent events, newest first. It returns
+// ErrNoEventLog if there is no event log.
+func (g *GameState) RecentEvents(count int) ([]events.Event, error) {
+	if g.eventLog == nil {
+		return nil, ErrNoEventLog
+	}
+	recent, err := g.eventLog.Read(events.ForGame(g.GameName()))
+	if err != nil {
+		return nil, err
+	}
+	if len(recent) > count {
+		recent = recent[len(recent)-count:]
+	}
+	slices.Reverse(recent)
+	return recent, nil
+}
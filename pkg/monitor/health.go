@@ -0,0 +1,32 @@
+package monitor
+
+import (
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
+)
+
+// PollInterval is how often the watch directory is scanned for new saves.
+const PollInterval = 5 * time.Second
+
+// recordPoll notes the outcome of a directory poll, for readiness checks.
+func (g *GameState) recordPoll(err error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastPoll = nowFrom(g.clock)
+	g.pollErr = err
+
+	if err != nil {
+		metrics.Polls.Inc("error")
+	} else {
+		metrics.Polls.Inc("ok")
+	}
+}
+
+// LastPoll returns when the watch directory was last polled and the error that poll ran into, if any.
+// The time is zero if no poll has completed yet.
+func (g *GameState) LastPoll() (time.Time, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.lastPoll, g.pollErr
+}
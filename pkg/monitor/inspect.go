@@ -0,0 +1,69 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/savefile"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// Save inspection modes, set with SAVE_INSPECTION.
+const (
+	inspectionOff    = config.InspectionOff
+	inspectionLog    = config.InspectionLog
+	inspectionOn     = config.InspectionOn
+	inspectionStrict = config.InspectionStrict
+)
+
+// checkSaveFile inspects a save before it hands over the turn. It returns false if the save was
+// rejected, in which case saver (the player who should have made it) has been asked to save again.
+func checkSaveFile(path, filename string, claimedTurn int, claimedPlayer, saver userparser.UserMapping, config *settings) bool {
+	if config.saveInspection == inspectionOff {
+		return true
+	}
+
+	info, err := inspectSave(config.fs, path)
+	if err != nil {
+		fmt.Printf("❌ Error inspecting save file %s: %v\n", filename, err)
+		return true // Don't block the game on a read error; the debounce will catch real write problems.
+	}
+
+	check := info.Validate(config.saveMinSize, claimedTurn, claimedPlayer.Username)
+	for _, warning := range check.Warnings {
+		fmt.Printf("⚠️ Save file %s: %s\n", filename, warning)
+	}
+	for _, problem := range check.Errors {
+		fmt.Printf("❌ Save file %s: %s\n", filename, problem)
+	}
+
+	problems := check.Errors
+	if config.saveInspection == inspectionStrict {
+		problems = append(problems, check.Warnings...)
+	}
+	if len(problems) == 0 {
+		fmt.Printf("🔍 Save file %s looks valid (%s, %d bytes)\n", filename, info.Format, info.Size)
+		return true
+	}
+	if config.saveInspection == inspectionLog {
+		fmt.Printf("🔍 Save file %s would be rejected with SAVE_INSPECTION=on, handing it over anyway\n", filename)
+		return true
+	}
+
+	fmt.Printf("🚫 Rejecting save file %s, asking %s to save again\n", filename, saver.Username)
+	if err := webhook.SendInvalidSaveWebHook(saver, filename, problems); err != nil {
+		fmt.Printf("❌ Failed to send invalid save notification for %s: %v\n", filename, err)
+	}
+	return false
+}
+
+// inspectSave opens the save at path on fsys and inspects it.
+func inspectSave(fsys FileSystem, path string) (*savefile.Info, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return savefile.Inspect(f)
+}
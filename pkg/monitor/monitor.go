@@ -1,36 +1,52 @@
 package monitor
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/archive"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/schedule"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
 )
 
-// FileTrackingInfo stores information about when a file was first seen and whether it has been processed.
+// FileTrackingInfo stores information about when a file was first seen, what its content looked like
+// on the last poll, and whether it has been processed.
 type FileTrackingInfo struct {
-	FirstSeen int64  // Timestamp when the file was first detected.
-	Processed bool   // Flag indicating if the file has been processed.
+	FirstSeen   int64  // Timestamp when the file was first detected.
+	StableSince int64  // Timestamp since which size, modification time and hash have not changed.
+	Size        int64  // File size on the last poll.
+	ModTime     int64  // File modification time (UnixMilli) on the last poll.
+	Hash        string // SHA-256 of the content on the last poll, if hashing is enabled.
+	Processed   bool   // Flag indicating if the file has been processed.
 }
 
-// parseIgnorePatterns parses comma-separated ignore patterns from the environment variable IGNORE_PATTERNS.
-// These patterns are used to determine which files should be ignored.
-func parseIgnorePatterns() []string {
-	patterns := os.Getenv("IGNORE_PATTERNS") // Get the value of the environment variable.
-	if patterns == "" {
-		return []string{} // Return an empty slice if the variable is not set.
-	}
-	var result []string
-	for _, pattern := range strings.Split(patterns, ",") { // Split the string by commas.
-		result = append(result, strings.ToLower(strings.TrimSpace(pattern))) // Trim spaces and convert to lowercase.
-	}
-	return result
+// settings holds the monitor options, taken from the configuration at startup.
+type settings struct {
+	fileDebounceMs int                // How long a file's content must be unchanged before it is processed.
+	ignorePatterns []string           // Filename substrings that are never processed.
+	hashFiles      bool               // Whether to hash file content as part of the stability check.
+	fileCheckTime  time.Duration      // How often to check the age of the latest save.
+	fileAgeLimit   time.Duration      // How old the latest save may be before the player is warned.
+	saveInspection string             // Save file inspection mode: off, log, on or strict.
+	saveMinSize    int64              // Smallest file accepted as a save when inspecting.
+	archive        *archive.Archive   // Versioned archive of processed saves, or nil if disabled.
+	autoRename     string             // Automatic rename mode for misnamed saves: off, rename or copy.
+	checkSequence  bool               // Whether saves must follow the expected turn order.
+	statsInterval  time.Duration      // How often to post a turn time summary, zero to never post one.
+	roundSummary   bool               // Whether to post a summary when each round is complete.
+	digestSchedule *schedule.Schedule // When to post a digest of the game, or nil to never post one.
+	fs             FileSystem         // Access to the watch directory.
 }
 
 // shouldIgnoreFile checks if a filename contains any of the ignore patterns.
@@ -49,82 +65,286 @@ func shouldIgnoreFile(filename string, ignorePatterns []string) bool {
 	return false
 }
 
-// MonitorDirectory monitors a directory for new save files and notifies the next player.
-// This is the main function that starts the monitoring process.
-func MonitorDirectory(dirPath string) {
-	// Get username to Discord ID mappings from the environment variable USER_MAPPINGS.
-	userMappings, err := userparser.ParseUsers("USER_MAPPINGS")
+// Poller scans the watch directory once per call to Poll, handing over turns as saves arrive and
+// sending reminders, warnings and summaries as they fall due.
+type Poller struct {
+	dirPath       string
+	fileTracker   map[string]*FileTrackingInfo
+	state         *GameState
+	config        *settings
+	lastCheckTime time.Time
+}
+
+// Monitor watches a game's directory for new saves and notifies the next player, until it is stopped.
+// It can be embedded in another program: create it with New and call Run, cancelling the context or
+// calling Stop to shut it down.
+type Monitor struct {
+	cfg   *config.Config
+	state *GameState
+	fs    FileSystem
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc // Stops the current run, nil if the monitor isn't running.
+	done     chan struct{}      // Closed when the current run has returned.
+	reloaded *config.Config     // Configuration to apply before the next poll, nil if there isn't one.
+}
+
+// New creates a monitor for the configured watch directory. The given state is updated as turns are
+// handed over, so other components can observe it. If state is nil, the game is tracked in memory only.
+func New(cfg *config.Config, state *GameState) *Monitor {
+	if state == nil {
+		state = NewGameState(cfg.GameName, cfg.Players)
+	}
+	return &Monitor{cfg: cfg, state: state, fs: OSFileSystem{}}
+}
+
+// State returns the game state the monitor updates.
+func (m *Monitor) State() *GameState {
+	return m.state
+}
+
+// Run polls the watch directory until ctx is cancelled or Stop is called. A poll in progress is always
+// finished first, so a turn is never left half handed over. It returns an error if the directory can't
+// be read when starting, or if the monitor is already running.
+func (m *Monitor) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.done != nil {
+		m.mu.Unlock()
+		return errors.New("monitor is already running")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	m.cancel, m.done = cancel, done
+	cfg := m.cfg
+	m.reloaded = nil
+	m.mu.Unlock()
+
+	defer func() {
+		cancel()
+		m.mu.Lock()
+		m.cancel, m.done = nil, nil
+		m.mu.Unlock()
+		close(done)
+	}()
+
+	poller, err := NewPoller(cfg, m.state, m.fs)
 	if err != nil {
-		log.Fatalf("❌ Failed to parse USER_MAPPINGS: %v. Please check the format (e.g., '1 User1 ID1,2 User2 ID2').", err)
+		return fmt.Errorf("reading directory: %w", err)
+	}
+
+	// Set up polling interval (check every 5 seconds).
+	pollInterval := PollInterval
+
+	fmt.Printf("👁️ Started monitoring directory: %s (polling every %v)\n", cfg.WatchDirectory, pollInterval)
+
+	ticker := time.NewTicker(pollInterval) // Create a ticker that ticks every pollInterval.
+	defer ticker.Stop()                    // Ensure the ticker is stopped when the function exits.
+
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Printf("🛑 Stopped monitoring directory: %s\n", cfg.WatchDirectory)
+			return nil
+		case <-ticker.C:
+			if reloaded := m.takeReloaded(); reloaded != nil {
+				poller.Reload(reloaded)
+			}
+			started := time.Now()
+			poller.Poll()
+			metrics.PollDuration.Set(time.Since(started).Seconds())
+		}
+	}
+}
+
+// Stop stops the monitor and waits for Run to return. It does nothing if the monitor isn't running.
+func (m *Monitor) Stop() {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.mu.Unlock()
+	if cancel == nil {
+		return
 	}
+	cancel()
+	<-done
+}
+
+// Reload applies a new configuration while the monitor runs. The players are updated at once and the
+// other settings before the next poll. The game name and watch directory can't be changed this way,
+// so they are kept.
+func (m *Monitor) Reload(cfg *config.Config) {
+	m.mu.Lock()
+	updated := *cfg
+	updated.GameName = m.cfg.GameName
+	updated.WatchDirectory = m.cfg.WatchDirectory
+	m.cfg = &updated
+	m.reloaded = &updated
+	m.mu.Unlock()
+
+	m.state.SetPlayers(updated.Players)
+}
+
+// takeReloaded returns the configuration passed to Reload since it was last called, or nil.
+func (m *Monitor) takeReloaded() *config.Config {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cfg := m.reloaded
+	m.reloaded = nil
+	return cfg
+}
+
+// NewPoller prepares to monitor the configured watch directory through fsys, using the state's clock.
+// The files already in the directory are treated as processed.
+func NewPoller(cfg *config.Config, state *GameState, fsys FileSystem) (*Poller, error) {
+	dirPath := cfg.WatchDirectory
+	userMappings := state.Players()
 
-	// Parse ignore patterns from the environment variable.
-	ignorePatterns := parseIgnorePatterns()
-	if len(ignorePatterns) > 0 {
-		fmt.Printf("🚫 Loaded %d ignore patterns\n", len(ignorePatterns))
+	if len(cfg.IgnorePatterns) > 0 {
+		fmt.Printf("🚫 Loaded %d ignore patterns\n", len(cfg.IgnorePatterns))
 	}
 
 	// Log the parsed user mappings.  This is helpful for debugging.
 	fmt.Printf("👥 Loaded %d user mappings:\n", len(userMappings))
 	for _, mapping := range userMappings {
-		fmt.Printf("  - Order: %d, User: %s, ID: %s\n", mapping.Order, mapping.Username, mapping.DiscordID)
+		fmt.Printf("  - Order: %d, User: %s, ID: %s, Notify: %s\n", mapping.Order, mapping.Username, mapping.DiscordID, mapping.Notify)
 	}
 
 	// File tracking map with timestamps to implement debouncing.
-	// The key is the filename (lowercase), and the value is a pointer to a FileTrackingInfo struct.
+	// The key is the filename as it is on disk, and the value is a pointer to a FileTrackingInfo struct.
 	fileTracker := make(map[string]*FileTrackingInfo)
 
-	// Current turn tracking.  This is initialized to 1 and updated as new files are processed.
-	currentTurn := 1
-
-	// Get file debounce time from the environment or default to 30 seconds.
 	// Debouncing is used to ensure that a file is completely written before it's processed.
-	fileDebounceMs := 30000 // Default to 30000 milliseconds (30 seconds).
-	if debounceEnv := os.Getenv("FILE_DEBOUNCE_MS"); debounceEnv != "" {
-		if parsed, err := strconv.Atoi(debounceEnv); err == nil {
-			fileDebounceMs = parsed // Override with the value from the environment variable if it's valid.
-		}
+	fmt.Printf("⏱️ File debounce time set to %d seconds\n", int(cfg.FileDebounce.Seconds()))
+	if cfg.FileHashCheck {
+		fmt.Println("#️⃣ Content hashing enabled for the stability check")
 	}
-	fmt.Printf("⏱️ File debounce time set to %d seconds\n", fileDebounceMs/1000)
+	fmt.Printf("🔍 Save file inspection: %s (minimum size %d bytes)\n", cfg.SaveInspection, cfg.SaveMinSize)
 
-	// Initialize tracker with existing files as already processed.
-	files, err := os.ReadDir(dirPath)
-	if err != nil {
-		fmt.Printf("❌ Error reading directory: %v\n", err)
-		return // Exit the function if there's an error reading the directory.
+	config := newSettings(cfg, state, fsys)
+
+	// Optionally keep a versioned copy of every processed save.
+	if config.archive != nil {
+		fmt.Printf("🗄️ Archiving processed saves to %s\n", config.archive.Dir())
 	}
 
-	for _, file := range files {
-		if !file.IsDir() { // Only process files, not directories.
-			lowerFilename := strings.ToLower(file.Name())
-			fileTracker[lowerFilename] = &FileTrackingInfo{
-				FirstSeen: time.Now().UnixMilli(), // Use the current time.
-				Processed: true,                 // Mark existing files as processed.
-			}
+	if cfg.AutoRename != autoRenameOff {
+		fmt.Printf("✏️ Misnamed saves will be fixed automatically (%s)\n", cfg.AutoRename)
+	}
+	if !cfg.TurnValidation {
+		fmt.Println("🧭 Turn order validation disabled")
+	}
+
+	// Summaries and digests are built from the turn history, so they need one to be kept
+	keepsHistory := state.keepsHistory()
+	var needHistory []string
+	if cfg.StatsSummaryInterval > 0 {
+		if keepsHistory {
+			fmt.Printf("📊 Posting a turn summary every %v\n", cfg.StatsSummaryInterval)
+		} else {
+			needHistory = append(needHistory, "STATS_SUMMARY_INTERVAL")
+		}
+	}
+	if cfg.RoundSummary {
+		if keepsHistory {
+			fmt.Println("🏁 Posting a summary at the end of each round")
+		} else {
+			needHistory = append(needHistory, "ROUND_SUMMARY")
 		}
 	}
+	if cfg.DigestSchedule != nil {
+		if keepsHistory {
+			fmt.Printf("📰 Posting a digest on the schedule %q (next at %s)\n", cfg.DigestSchedule, cfg.DigestSchedule.Next(state.now()).Format(time.DateTime))
+		} else {
+			needHistory = append(needHistory, "DIGEST_SCHEDULE")
+		}
+	}
+	if len(needHistory) > 0 {
+		fmt.Printf("⚠️ %s need a turn history, but HISTORY_FILE is not set, so nothing will be posted for them\n", strings.Join(needHistory, ", "))
+	}
+
+	// Skip players who don't take their turn in time.
+	if cfg.TurnDeadline > 0 {
+		fmt.Printf("⌛ Turn deadline set to %v, with a final warning %v before\n", cfg.TurnDeadline, cfg.TurnDeadlineWarning)
+		if cfg.TurnDeadlineMaxSkips > 0 {
+			fmt.Printf("⌛ Players are only skipped automatically %d times\n", cfg.TurnDeadlineMaxSkips)
+		}
+	}
+
+	// Initialize tracker with existing files as already processed.
+	now := state.now()
+	if err := trackExistingFiles(fsys, dirPath, fileTracker, now); err != nil {
+		return nil, err
+	}
 	fmt.Printf("📋 Initialized with %d existing files\n", len(fileTracker))
 
-	// Set up polling interval (check every 5 seconds).
-	pollInterval := 5 * time.Second
+	return &Poller{
+		dirPath:       dirPath,
+		fileTracker:   fileTracker,
+		state:         state,
+		config:        config,
+		lastCheckTime: now,
+	}, nil
+}
 
-	fmt.Printf("👁️ Started monitoring directory: %s (polling every %v)\n", dirPath, pollInterval)
+// newSettings takes the monitor options from the configuration, and sets the state's deadline policy.
+func newSettings(cfg *config.Config, state *GameState, fsys FileSystem) *settings {
+	var saveArchive *archive.Archive
+	if cfg.ArchiveDirectory != "" {
+		saveArchive = archive.New(cfg.ArchiveDirectory, state.Snapshot().GameName, cfg.ArchiveRetention)
+	}
+	state.setDeadlinePolicy(deadlineFromConfig(cfg))
+
+	return &settings{
+		fileDebounceMs: int(cfg.FileDebounce.Milliseconds()),
+		ignorePatterns: cfg.IgnorePatterns,
+		hashFiles:      cfg.FileHashCheck,
+		fileCheckTime:  cfg.FileCheckTime,
+		fileAgeLimit:   cfg.FileAgeLimit,
+		saveInspection: cfg.SaveInspection,
+		saveMinSize:    cfg.SaveMinSize,
+		archive:        saveArchive,
+		autoRename:     cfg.AutoRename,
+		checkSequence:  cfg.TurnValidation,
+		statsInterval:  cfg.StatsSummaryInterval,
+		roundSummary:   cfg.RoundSummary,
+		digestSchedule: cfg.DigestSchedule,
+		fs:             fsys,
+	}
+}
 
-	ticker := time.NewTicker(pollInterval) // Create a ticker that ticks every pollInterval.
-	defer ticker.Stop()                   // Ensure the ticker is stopped when the function exits.
-	// Initialize lastCheckTime
-	lastCheckTime := time.Now()
+// Poll scans the watch directory once.
+func (p *Poller) Poll() {
+	processDirectory(p.dirPath, p.fileTracker, p.state, p.config, &p.lastCheckTime)
+}
+
+// Reload replaces the monitor options with those from cfg. The watch directory is not changed, and
+// files already seen are not processed again.
+func (p *Poller) Reload(cfg *config.Config) {
+	p.config = newSettings(cfg, p.state, p.config.fs)
+}
 
-	for range ticker.C {
-		currentTurn = processDirectory(dirPath, fileTracker, userMappings, fileDebounceMs, ignorePatterns, currentTurn, &lastCheckTime)
+// trackExistingFiles resets the tracker to the files currently in the directory, all marked as processed.
+func trackExistingFiles(fsys FileSystem, dirPath string, fileTracker map[string]*FileTrackingInfo, now time.Time) error {
+	files, err := fsys.ReadDir(dirPath)
+	if err != nil {
+		return err
 	}
+
+	clear(fileTracker)
+	for _, file := range files {
+		if !file.IsDir() { // Only process files, not directories.
+			info := newFileTrackingInfo(file, now.UnixMilli())
+			info.Processed = true // Mark existing files as processed.
+			fileTracker[file.Name()] = info
+		}
+	}
+	return nil
 }
 
 // extractTurnNumber attempts to extract the turn number from a filename.
 // It uses a regular expression to find the turn number in the filename.
 func extractTurnNumber(filename string) int {
 	// First try the standard pattern: something_turn#_something
-	turnPattern := regexp.MustCompile(`_turn(\d+)_`) // Regular expression to find "_turn<number>_".
+	turnPattern := regexp.MustCompile(`_turn(\d+)_`)                     // Regular expression to find "_turn<number>_".
 	matches := turnPattern.FindStringSubmatch(strings.ToLower(filename)) // Find the pattern in the lowercase filename.
 
 	if len(matches) > 1 { // Check if there's a match (matches[0] is the whole string, matches[1] is the captured group).
@@ -137,18 +357,25 @@ func extractTurnNumber(filename string) int {
 }
 
 // processDirectory handles a single directory scan iteration.
-// Returns the current turn number (possibly updated).
+// The turn counter and current player are updated in state.
 func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
-	userMappings []userparser.UserMapping,
-	fileDebounceMs int, ignorePatterns []string, currentTurn int, lastCheckTime *time.Time) int {
+	state *GameState, config *settings, lastCheckTime *time.Time) {
+
+	// If the state was changed by another process (e.g. a rollback), adopt it and treat the files
+	// currently in the directory as the new baseline.
+	if state.reloadIfChanged() {
+		if err := trackExistingFiles(config.fs, dirPath, fileTracker, state.now()); err != nil {
+			fmt.Printf("❌ Error reading directory: %v\n", err)
+			state.recordPoll(err)
+			return
+		}
+	}
 
-	now := time.Now().UnixMilli()
+	now := state.now().UnixMilli()
+	userMappings := state.Players()
 
 	// Get the configured game name
-	gameName := strings.ToLower(os.Getenv("GAME_NAME"))
-	if gameName == "" {
-		gameName = "pbem1"
-	}
+	gameName := state.GameName()
 
 	// Track current files to detect deleted ones
 	currentFiles := make(map[string]bool)
@@ -156,40 +383,65 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 	var latestFileName string
 
 	// Read all files in directory
-	files, err := os.ReadDir(dirPath)
+	files, err := config.fs.ReadDir(dirPath)
 	if err != nil {
 		fmt.Printf("❌ Error reading directory: %v\n", err)
-		return currentTurn
+		state.recordPoll(err)
+		return
+	}
+
+	// Resume a paused game once its resume date has passed
+	state.resumeIfDue()
+	state.enforceDeadline()
+	state.sendSummaryIfDue(config.statsInterval)
+	state.sendDigestIfDue(config.digestSchedule)
+	if config.roundSummary {
+		state.sendRoundSummaryIfDue()
 	}
 
+	// See whether a requested rename has been done
+	checkPendingRename(files, state)
+
 	// Process each file
 	for _, file := range files {
 		if file.IsDir() {
 			continue
 		}
 
+		// Files are tracked by their name on disk, so names differing only in case are kept apart,
+		// but matched against the game and players in lowercase.
 		filename := strings.ToLower(file.Name())
-		currentFiles[filename] = true
 
-		// Try to extract turn number from filename
-		if turnNumber := extractTurnNumber(filename); turnNumber > currentTurn {
-			currentTurn = turnNumber
-			fmt.Printf("🔢 Updated current turn to %d based on filename: %s\n", currentTurn, filename)
+		// Skip partial and temporary files written by sync tools while they transfer
+		if isTemporarySyncFile(filename) {
+			continue
+		}
+
+		currentFiles[file.Name()] = true
+
+		// Conflict copies of saves are reported to the game rather than processed as new saves
+		if original, isConflict := conflictCopyOriginal(filename); isConflict && looksLikeSave(original, gameName, userMappings) {
+			handleConflictCopy(dirPath, file, original, fileTracker, now, config)
+			continue
 		}
 
-		if info, exists := fileTracker[filename]; !exists {
+		info, exists := fileTracker[file.Name()]
+		if !exists {
 			// New file detected
-			fmt.Printf("📄 New save file detected: %s, starting debounce period\n", filename)
-			fileTracker[filename] = &FileTrackingInfo{
-				FirstSeen: now,
-				Processed: false,
-			}
-		} else if !info.Processed && (now-info.FirstSeen) >= int64(fileDebounceMs) {
-			// File has been stable for debounce period
-			fmt.Printf("⏱️ File %s stable for %ds, processing now\n", filename, fileDebounceMs/1000)
+			fmt.Printf("📄 New save file detected: %s, starting debounce period\n", file.Name())
+			fileTracker[file.Name()] = newFileTrackingInfo(file, now)
+		} else {
+			// Refresh size, modification time and hash. A processed save that was overwritten
+			// in place is reopened as a resubmission.
+			updateStability(config.fs, info, file.Name(), filepath.Join(dirPath, file.Name()), file, now, config.hashFiles)
+		}
+
+		if exists && !info.Processed && (now-info.StableSince) >= int64(config.fileDebounceMs) {
+			// File content has been stable for debounce period
+			fmt.Printf("⏱️ File %s stable for %ds, processing now\n", file.Name(), config.fileDebounceMs/1000)
 
 			// Check if the file should be ignored
-			if shouldIgnoreFile(filename, ignorePatterns) {
+			if shouldIgnoreFile(filename, config.ignorePatterns) {
 				fmt.Printf("🚫 Ignoring file %s based on ignore patterns\n", filename)
 				info.Processed = true
 				continue
@@ -197,81 +449,17 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 
 			// Check if the game name in the filename matches the configured game name
 			if !strings.HasPrefix(filename, gameName) {
-				fmt.Printf("⚠️ File %s doesn't match configured game name '%s'\n", filename, gameName)
-
-				// Try to find which user *might* have saved this based on filename content
-				var foundUserIndex = -1 // Index in the userMappings slice
-				for i, mapping := range userMappings {
-					if strings.Contains(filename, strings.ToLower(mapping.Username)) {
-						foundUserIndex = i
-						break
-					}
-				}
-
-				// Find the previous user who should be notified about the naming issue
-				if foundUserIndex != -1 {
-					// Determine the index of the user who *should* have saved (previous user in order)
-					previousUserIndex := (foundUserIndex - 1 + len(userMappings)) % len(userMappings)
-					previousUserMapping := userMappings[previousUserIndex]
-
-					fmt.Printf("🔔 Sending rename notification to previous user %s (%s) for incorrectly named file %s\n",
-						previousUserMapping.Username, previousUserMapping.DiscordID, filename)
-					webhook.SendRenameWebHook(previousUserMapping.Username, previousUserMapping.DiscordID, filename, currentTurn)
-
-				} else {
-					fmt.Printf("❓ Cannot identify any user for incorrectly named file: %s. Cannot determine who to notify.\n", filename)
-				}
-
+				handleMisnamedSave(dirPath, file.Name(), fileTracker, state, config)
 				info.Processed = true
 				continue
 			}
 
-			// Find username in filename to identify the player whose turn it *is*
-			var currentPlayerIndex = -1 // Index in the userMappings slice
-			for i, mapping := range userMappings {
-				// Check if the filename contains the *current* player's username (case-insensitive)
-				if strings.Contains(filename, strings.ToLower(mapping.Username)) {
-					currentPlayerIndex = i
-					break
-				}
-			}
-
-			if currentPlayerIndex != -1 {
-				// The user found in the filename is the *current* player
-				currentUserMapping := userMappings[currentPlayerIndex]
-
-				// Determine the index of the *next* player in the order
-				nextPlayerIndex := (currentPlayerIndex + 1) % len(userMappings)
-				nextUserMapping := userMappings[nextPlayerIndex]
-
-				// Determine the index of the player who just finished (previous player)
-				previousPlayerIndex := (currentPlayerIndex - 1 + len(userMappings)) % len(userMappings)
-				previousUserMapping := userMappings[previousUserIndex]
-
-				// Determine the turn number for the *next* save file instruction
-				saveInstructionTurnNumber := currentTurn
-				// Check if the *current* player (whose file we are processing) is the last in the order.
-				// If so, the save instruction should be for the *next* turn.
-				if currentPlayerIndex == len(userMappings)-1 {
-					saveInstructionTurnNumber = currentTurn + 1
-					fmt.Printf("🔄 Last player (%s) finished turn %d, next save will start turn %d\n", currentUserMapping.Username, currentTurn, saveInstructionTurnNumber)
-					// Update the main turn counter *after* processing this file and determining the instruction number
-					currentTurn = saveInstructionTurnNumber
-				}
-
-				fmt.Printf("🔄 Turn %d: It's %s's turn (save from %s). Next up: %s (for turn %d)\n", currentTurn, currentUserMapping.Username, previousUserMapping.Username, nextUserMapping.Username, saveInstructionTurnNumber)
-
-				// Send webhook to the *current* player, instructing them to save for the *next* player, using the correct turn number for the save instruction
-				webhook.SendWebHook(currentUserMapping.Username, currentUserMapping.DiscordID, nextUserMapping.Username, saveInstructionTurnNumber)
-
-				info.Processed = true
-			} else {
-				fmt.Printf("❓ Cannot match any user to save file: %s\n", filename)
-				info.Processed = true
-			}
+			// Hand the turn over to the player named in the file
+			handOverTurn(dirPath, file.Name(), state, config)
+			info.Processed = true
 		}
 		//check for the latest file
-		fileInfo, err := os.Stat(dirPath + "/" + file.Name())
+		fileInfo, err := config.fs.Stat(filepath.Join(dirPath, file.Name()))
 		if err != nil {
 			fmt.Printf("Error getting file info for %s: %v\n", file.Name(), err)
 			continue
@@ -281,46 +469,31 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 			latestFileName = file.Name()
 		}
 	}
-	checkFileAge(latestFileTime, latestFileName, lastCheckTime, userMappings)
-	// Clean up tracking for deleted files
-	for filename := range fileTracker {
-		if !currentFiles[filename] {
+	// No reminders while the game is paused
+	if !state.Paused() {
+		checkFileAge(latestFileTime, latestFileName, lastCheckTime, userMappings, state, config)
+	}
+	// Clean up tracking for deleted files. Entries first seen during this poll belong to files
+	// created by the bot itself (such as automatic renames) and are kept.
+	for filename, info := range fileTracker {
+		if !currentFiles[filename] && info.FirstSeen < now {
 			delete(fileTracker, filename)
 			fmt.Printf("🗑️ Removed tracking for deleted file: %s\n", filename)
 		}
 	}
-
-	return currentTurn
+	state.recordPoll(nil)
 }
 
 // checkFileAge checks the age of the latest file and sends a Discord notification if it exceeds the limit.
-func checkFileAge(latestFileTime int64, latestFileName string, lastCheckTime *time.Time, userMappings []userparser.UserMapping) {
-	fileCheckTime := 24 * time.Hour //check every 24 hours
-	fileCheckTimeEnv := os.Getenv("FILE_CHECK_TIME")
-	if fileCheckTimeEnv != "" {
-		duration, err := time.ParseDuration(fileCheckTimeEnv)
-		if err != nil {
-			log.Printf("Invalid FILE_CHECK_TIME value: %s. Using default (24h).\n", fileCheckTimeEnv)
-		} else {
-			fileCheckTime = duration
-		}
-	}
-
-	now := time.Now()
-	if now.Sub(*lastCheckTime) >= fileCheckTime {
+// Time the current turn spent paused doesn't count towards the file's age, and warnings sent to a
+// player are counted as reminders in the turn history.
+func checkFileAge(latestFileTime int64, latestFileName string, lastCheckTime *time.Time, userMappings []userparser.UserMapping, state *GameState, config *settings) {
+	now := state.now()
+	if now.Sub(*lastCheckTime) >= config.fileCheckTime {
 		*lastCheckTime = now
-		fileAgeLimit := 24 * time.Hour // Default to 24 hours
-		fileAgeLimitEnv := os.Getenv("FILE_AGE_LIMIT")
-		if fileAgeLimitEnv != "" {
-			duration, err := time.ParseDuration(fileAgeLimitEnv)
-			if err != nil {
-				log.Printf("Invalid FILE_AGE_LIMIT value: %s. Using default (24h).\n", fileAgeLimitEnv)
-			} else {
-				fileAgeLimit = duration
-			}
-		}
+		fileAgeLimit := config.fileAgeLimit
 
-		fileAge := time.Duration(now.Unix() - latestFileTime) * time.Second
+		fileAge := time.Duration(now.Unix()-latestFileTime)*time.Second - state.Snapshot().TurnPaused
 
 		if fileAge > fileAgeLimit {
 			fmt.Printf("⏰ Latest file (%s) is older than %v (%v old). Sending Discord notification.\n", latestFileName, fileAgeLimit, fileAge)
@@ -335,14 +508,115 @@ func checkFileAge(latestFileTime int64, latestFileName string, lastCheckTime *ti
 			}
 			if currentPlayerIndex != -1 {
 				currentUserMapping := userMappings[currentPlayerIndex]
-				webhook.SendFileAgeWarningWebHook(latestFileName, fileAge, fileAgeLimit, currentUserMapping.Username, currentUserMapping.DiscordID)
+				webhook.SendFileAgeWarningWebHook(latestFileName, fileAge, fileAgeLimit, currentUserMapping)
+				state.noteReminder()
+				state.recordEvent(events.Stall, currentUserMapping.Username, state.Turn(),
+					fmt.Sprintf("Waiting on %s for %s", currentUserMapping.Username, fileAge.Round(time.Minute)),
+					fmt.Sprintf("The latest save, %s, is older than %s.", latestFileName, fileAgeLimit))
 			} else {
-				webhook.SendFileAgeWarningWebHook(latestFileName, fileAge, fileAgeLimit, "", "")
+				webhook.SendFileAgeWarningWebHook(latestFileName, fileAge, fileAgeLimit, userparser.UserMapping{})
 			}
 
 		} else {
 			fmt.Printf("Latest file (%s) is %v old, which is within the limit (%v).\n", latestFileName, fileAge, fileAgeLimit)
 		}
 	}
+
+	// Work out when a check will next find the file over the limit, for the status API
+	if latestFileTime > 0 {
+		overLimit := time.Unix(latestFileTime, 0).Add(state.Snapshot().TurnPaused + config.fileAgeLimit)
+		state.setNextReminder(nextReminder(*lastCheckTime, config.fileCheckTime, overLimit))
+	}
 }
 
+// nextReminder returns the first check after lastCheck, one every checkInterval, that comes after
+// overLimit.
+func nextReminder(lastCheck time.Time, checkInterval time.Duration, overLimit time.Time) time.Time {
+	// A zero interval checks on every poll
+	checkInterval = max(checkInterval, PollInterval)
+	next := lastCheck.Add(checkInterval)
+	if !next.After(overLimit) {
+		next = next.Add((overLimit.Sub(next)/checkInterval + 1) * checkInterval)
+	}
+	return next
+}
+
+// archiveSave copies a processed save into the archive, if one is configured, recording it as
+// archived at now.
+func archiveSave(path string, turn int, player string, now time.Time, config *settings) {
+	if config.archive == nil {
+		return
+	}
+	f, err := config.fs.Open(path)
+	if err != nil {
+		fmt.Printf("❌ Failed to archive save %s: %v\n", path, err)
+		return
+	}
+	defer f.Close()
+	entry, err := config.archive.Store(f, filepath.Base(path), turn, player, now)
+	if err != nil {
+		fmt.Printf("❌ Failed to archive save %s: %v\n", path, err)
+		return
+	}
+	fmt.Printf("🗄️ Archived %s as %s (%d bytes)\n", entry.SourceName, entry.File, entry.Size)
+}
+
+// handOverTurn processes a stable, correctly named save: it identifies the player named in the
+// file, checks and archives the save, records the new turn and notifies that player. It returns false if
+// the save was not handed over.
+func handOverTurn(dirPath, name string, state *GameState, config *settings) bool {
+	filename := strings.ToLower(name)
+	userMappings := state.Players()
+
+	// Find username in filename to identify the player whose turn it *is*
+	currentPlayerIndex := indexOfPlayerInFilename(userMappings, filename)
+	if currentPlayerIndex == -1 {
+		fmt.Printf("❓ Cannot match any user to save file: %s\n", filename)
+		return false
+	}
+
+	// The user found in the filename is the *current* player
+	currentUserMapping := userMappings[currentPlayerIndex]
+
+	// Make sure the save is the one the game is waiting for, and determine the turn the current
+	// player is playing: the one in the filename, or the next one in the order
+	sequence := checkSequence(state.Snapshot(), name, extractTurnNumber(filename), currentPlayerIndex)
+	if sequence.Problem != sequenceOK {
+		if config.checkSequence {
+			metrics.SavesProcessed.Inc(state.GameName(), "out_of_order")
+			reportSequenceProblem(name, sequence, state)
+			return false
+		}
+		fmt.Printf("🧭 Turn order problem with %s (%s), continuing because validation is disabled\n", filename, sequence.Description)
+	}
+	currentTurn := sequence.Turn
+
+	// Determine the index of the *next* player in the order, and the turn number for
+	// the *next* save file instruction. If the *current* player is the last in the order,
+	// the save instruction should be for the *next* turn.
+	nextPlayerIndex, saveInstructionTurnNumber := state.nextPlayer(currentPlayerIndex, currentTurn)
+	nextUserMapping := userMappings[nextPlayerIndex]
+	if saveInstructionTurnNumber != currentTurn {
+		fmt.Printf("🔄 Last player (%s) is playing turn %d, next save will start turn %d\n", currentUserMapping.Username, currentTurn, saveInstructionTurnNumber)
+	}
+
+	// Determine the player who just finished (previous player)
+	previousPlayerIndex := (currentPlayerIndex - 1 + len(userMappings)) % len(userMappings)
+	previousUserMapping := userMappings[previousPlayerIndex]
+
+	// Make sure the file really is a save for this turn before handing it over
+	if !checkSaveFile(filepath.Join(dirPath, name), filename, extractTurnNumber(filename), currentUserMapping, previousUserMapping, config) {
+		metrics.SavesProcessed.Inc(state.GameName(), "rejected")
+		return false
+	}
+
+	archiveSave(filepath.Join(dirPath, name), currentTurn, currentUserMapping.Username, state.now(), config)
+
+	fmt.Printf("🔄 Turn %d: It's %s's turn (save from %s). Next up: %s (for turn %d)\n", currentTurn, currentUserMapping.Username, previousUserMapping.Username, nextUserMapping.Username, saveInstructionTurnNumber)
+	state.beginTurn(currentTurn, currentPlayerIndex, name, false)
+	metrics.SavesProcessed.Inc(state.GameName(), "handed_over")
+
+	// Send webhook to the *current* player, instructing them to save for the *next* player, using the correct turn number for the save instruction
+	webhook.SendWebHook(currentUserMapping, nextUserMapping.Username, saveInstructionTurnNumber)
+	return true
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// ErrGamePaused is returned by reminders and skips while the game is paused.
+var ErrGamePaused = errors.New("the game is paused")
+
+// Pause pauses the game, optionally until a given time (zero for no resume date). While paused,
+// reminders are suppressed and the time doesn't count towards the current turn's duration.
+// It reports whether anything changed. Pausing an already paused game updates the resume date and
+// reason if either is given.
+func (g *GameState) Pause(until time.Time, reason string) bool {
+	g.mu.Lock()
+	wasPaused := g.paused
+	if wasPaused && ((until.IsZero() && reason == "") || (g.pausedUntil.Equal(until) && g.pauseReason == reason)) {
+		g.mu.Unlock()
+		return false
+	}
+	if !wasPaused {
+		g.paused = true
+		g.pausedAt = nowFrom(g.clock)
+	}
+	g.pausedUntil = until
+	g.pauseReason = reason
+	g.mu.Unlock()
+	g.persist()
+
+	if until.IsZero() {
+		fmt.Println("⏸️ Game paused, reminders are suspended")
+	} else {
+		fmt.Printf("⏸️ Game paused until %s, reminders are suspended\n", until.Format(time.RFC1123))
+	}
+	detail := reason
+	if !until.IsZero() {
+		detail = strings.TrimSpace(fmt.Sprintf("%s Resumes on %s.", detail, until.Format(time.RFC1123)))
+	}
+	g.recordEvent(events.Paused, "", g.Turn(), "Game paused", detail)
+	if err := webhook.SendPauseWebHook(until, reason); err != nil {
+		fmt.Printf("❌ Failed to announce pause: %v\n", err)
+	}
+	return true
+}
+
+// Resume resumes a paused game and pings the current player. It reports whether the game was paused.
+func (g *GameState) Resume() bool {
+	g.mu.Lock()
+	if !g.paused {
+		g.mu.Unlock()
+		return false
+	}
+	now := nowFrom(g.clock)
+	pausedFor := now.Sub(g.pausedAt)
+	g.turnPaused += ongoingPause(true, g.pausedAt, g.turnStarted, now)
+	g.paused = false
+	g.pausedAt = time.Time{}
+	g.pausedUntil = time.Time{}
+	g.pauseReason = ""
+	g.mu.Unlock()
+	g.persist()
+
+	fmt.Printf("▶️ Game resumed after %s\n", pausedFor.Round(time.Second))
+	current, _ := g.Snapshot().CurrentMapping()
+	g.recordEvent(events.Resumed, current.Username, g.Turn(), "Game resumed",
+		fmt.Sprintf("The game was paused for %s.", pausedFor.Round(time.Minute)))
+	if err := webhook.SendResumeWebHook(current, pausedFor); err != nil {
+		fmt.Printf("❌ Failed to announce resume: %v\n", err)
+	}
+	return true
+}
+
+// resumeIfDue resumes the game once its resume date has passed.
+func (g *GameState) resumeIfDue() {
+	g.mu.RLock()
+	due := g.paused && !g.pausedUntil.IsZero() && !nowFrom(g.clock).Before(g.pausedUntil)
+	g.mu.RUnlock()
+
+	if due {
+		fmt.Println("📅 Resume date reached")
+		g.Resume()
+	}
+}
+
+// Paused reports whether the game is paused.
+func (g *GameState) Paused() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.paused
+}
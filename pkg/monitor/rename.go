@@ -0,0 +1,238 @@
+package monitor
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// Automatic rename modes, set with AUTO_RENAME.
+const (
+	autoRenameOff    = config.AutoRenameOff
+	autoRenameRename = config.AutoRenameRename
+	autoRenameCopy   = config.AutoRenameCopy
+)
+
+// looseTurnPattern finds turn numbers written without the surrounding underscores, e.g. "turn7" or "Turn 7".
+var looseTurnPattern = regexp.MustCompile(`turn[ _-]?(\d+)`)
+
+// handleMisnamedSave deals with a stable save whose name doesn't start with the game name. If automatic
+// renaming is enabled and the player and turn can be inferred unambiguously, the save is renamed (or
+// copied) to the expected name and the turn is handed over as usual. Otherwise the player is asked to
+// rename it.
+func handleMisnamedSave(dirPath, name string, fileTracker map[string]*FileTrackingInfo, state *GameState, config *settings) {
+	filename := strings.ToLower(name)
+	fmt.Printf("⚠️ File %s doesn't match configured game name '%s'\n", filename, state.GameName())
+
+	if config.autoRename != autoRenameOff {
+		if newName, saver, ok := autoRename(config.fs, dirPath, name, state, config.autoRename); ok {
+			// Track the new name as processed so it isn't picked up again as a new save
+			if entries, err := config.fs.ReadDir(dirPath); err == nil {
+				for _, entry := range entries {
+					if entry.Name() == newName {
+						info := newFileTrackingInfo(entry, state.now().UnixMilli())
+						info.Processed = true
+						fileTracker[newName] = info
+					}
+				}
+			}
+			// Only tell the saver about the fix once the renamed save has been accepted; if it was rejected,
+			// they have already been told why
+			if handOverTurn(dirPath, newName, state, config) {
+				if err := webhook.SendAutoRenameWebHook(saver, name, newName, config.autoRename == autoRenameCopy); err != nil {
+					fmt.Printf("❌ Failed to send auto-rename notification for %s: %v\n", name, err)
+				}
+			}
+			return
+		}
+	}
+
+	// Work out who made the save and exactly what it should have been called
+	saver, expectedName, ok := expectedRename(name, state)
+	if !ok {
+		fmt.Printf("❓ Cannot identify any user for incorrectly named file: %s. Cannot determine who to notify.\n", filename)
+		return
+	}
+
+	fmt.Printf("🔔 Sending rename notification to %s (%s) for incorrectly named file %s, expected %s\n",
+		saver.Username, saver.DiscordID, filename, expectedName)
+	state.setPendingRename(&PendingRename{
+		Original:  name,
+		Expected:  expectedName,
+		Saver:     saver.Username,
+		Requested: state.now(),
+	})
+	state.recordEvent(events.Rename, saver.Username, 0, fmt.Sprintf("Misnamed save %s", name),
+		fmt.Sprintf("%s was asked to rename it to %s.", saver.Username, expectedName))
+	if err := webhook.SendRenameWebHook(saver, name, expectedName); err != nil {
+		fmt.Printf("❌ Failed to ask %s to rename %s: %v\n", saver.Username, name, err)
+	}
+}
+
+// expectedRename determines who made a misnamed save and the exact name it should have.
+// When a turn is in progress, the current player made the save for the next player in the order.
+// Otherwise the player named in the file is assumed to be next, and the player before them made it.
+func expectedRename(name string, state *GameState) (userparser.UserMapping, string, bool) {
+	filename := strings.ToLower(name)
+	snapshot := state.Snapshot()
+	ext := filepath.Ext(name)
+
+	if current, ok := snapshot.CurrentMapping(); ok {
+		nextIndex, nextTurn := state.nextPlayer(snapshot.CurrentPlayer, snapshot.CurrentTurn)
+		return current, state.saveFileName(nextTurn, snapshot.Players[nextIndex].Username) + ext, true
+	}
+
+	foundIndex := indexOfPlayerInFilename(snapshot.Players, filename)
+	if foundIndex == -1 {
+		return userparser.UserMapping{}, "", false
+	}
+
+	turn := extractTurnNumber(filename)
+	if turn == 0 {
+		turn = turnNumberIn(filename)
+	}
+	if turn == 0 {
+		turn = snapshot.CurrentTurn
+	}
+	return saverOf(snapshot, foundIndex), state.saveFileName(turn, snapshot.Players[foundIndex].Username) + ext, true
+}
+
+// saverOf returns the player who made a save for the player at namedIndex: the current player when a turn
+// is in progress, otherwise the player before them in the order.
+func saverOf(snapshot Snapshot, namedIndex int) userparser.UserMapping {
+	if current, ok := snapshot.CurrentMapping(); ok {
+		return current
+	}
+	return snapshot.Players[(namedIndex-1+len(snapshot.Players))%len(snapshot.Players)]
+}
+
+// checkPendingRename looks for the corrected file after a rename was requested. Once it appears, the
+// request is cleared and the file goes through the normal turn flow like any other new save.
+func checkPendingRename(files []fs.DirEntry, state *GameState) {
+	pending := state.Snapshot().PendingRename
+	if pending == nil {
+		return
+	}
+
+	for _, file := range files {
+		if strings.EqualFold(file.Name(), pending.Expected) {
+			fmt.Printf("✅ Corrected save %s appeared (requested from %s %s ago), resuming the turn\n",
+				file.Name(), pending.Saver, state.now().Sub(pending.Requested).Round(time.Second))
+			state.setPendingRename(nil)
+			state.recordEvent(events.Rename, pending.Saver, 0, fmt.Sprintf("Save renamed to %s", file.Name()),
+				fmt.Sprintf("%s corrected %s.", pending.Saver, pending.Original))
+			return
+		}
+	}
+}
+
+// autoRename renames or copies a misnamed save to {game}_turn{n}_{player}{ext} when exactly one player is
+// named in the file and the turn is either in the filename or implied by the turn order. It returns the new
+// filename and the player who made the save, and false if the name could not be inferred or the file could
+// not be renamed.
+func autoRename(fsys FileSystem, dirPath, name string, state *GameState, mode string) (string, userparser.UserMapping, bool) {
+	filename := strings.ToLower(name)
+	snapshot := state.Snapshot()
+
+	// The player must be unambiguous: exactly one username may appear in the filename
+	playerIndex := -1
+	for i, mapping := range snapshot.Players {
+		if strings.Contains(filename, strings.ToLower(mapping.Username)) {
+			if playerIndex != -1 {
+				fmt.Printf("❓ Cannot auto-rename %s: it names both %s and %s\n", name, snapshot.Players[playerIndex].Username, mapping.Username)
+				return "", userparser.UserMapping{}, false
+			}
+			playerIndex = i
+		}
+	}
+	if playerIndex == -1 {
+		fmt.Printf("❓ Cannot auto-rename %s: no player is named in it\n", name)
+		return "", userparser.UserMapping{}, false
+	}
+	player := snapshot.Players[playerIndex]
+
+	// The turn comes from the filename, or from the turn order if this player is the one expected next
+	turn := extractTurnNumber(filename)
+	if turn == 0 {
+		turn = turnNumberIn(filename)
+	}
+	if turn == 0 {
+		if snapshot.CurrentPlayer < 0 {
+			fmt.Printf("❓ Cannot auto-rename %s: no turn number in the name and no turn in progress\n", name)
+			return "", userparser.UserMapping{}, false
+		}
+		expectedIndex, expectedTurn := state.nextPlayer(snapshot.CurrentPlayer, snapshot.CurrentTurn)
+		if expectedIndex != playerIndex {
+			fmt.Printf("❓ Cannot auto-rename %s: no turn number in the name and %s is not the next player\n", name, player.Username)
+			return "", userparser.UserMapping{}, false
+		}
+		turn = expectedTurn
+	}
+
+	newName := state.saveFileName(turn, player.Username) + filepath.Ext(name)
+	newPath := filepath.Join(dirPath, newName)
+	if _, err := fsys.Stat(newPath); err == nil {
+		fmt.Printf("❓ Cannot auto-rename %s: %s already exists\n", name, newName)
+		return "", userparser.UserMapping{}, false
+	}
+
+	oldPath := filepath.Join(dirPath, name)
+	var err error
+	if mode == autoRenameCopy {
+		err = copyWithin(fsys, oldPath, newPath)
+	} else {
+		err = fsys.Rename(oldPath, newPath)
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed to auto-rename %s to %s: %v\n", name, newName, err)
+		return "", userparser.UserMapping{}, false
+	}
+
+	verb := "Renamed"
+	if mode == autoRenameCopy {
+		verb = "Copied"
+	}
+	fmt.Printf("✏️ %s %s to %s\n", verb, name, newName)
+	state.recordEvent(events.Rename, "", 0, fmt.Sprintf("%s %s to %s", verb, name, newName),
+		"The misnamed save was fixed automatically.")
+	return newName, saverOf(snapshot, playerIndex), true
+}
+
+// turnNumberIn finds a loosely written turn number such as "turn7" or "turn 7" anywhere in a (lowercase) filename.
+func turnNumberIn(filename string) int {
+	if matches := looseTurnPattern.FindStringSubmatch(filename); matches != nil {
+		if turn, err := strconv.Atoi(matches[1]); err == nil {
+			return turn
+		}
+	}
+	return 0
+}
+
+// saveFileName returns the expected save name (without extension) for a player's turn.
+func (g *GameState) saveFileName(turn int, player string) string {
+	return fmt.Sprintf("%s_turn%d_%s", g.savePrefix, turn, player)
+}
+
+// copyWithin copies the file at src to dest on fsys, replacing dest if it exists.
+func copyWithin(fsys FileSystem, src, dest string) error {
+	f, err := fsys.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+	return fsys.WriteFile(dest, content)
+}
@@ -0,0 +1,181 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/archive"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/state"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// Rollback restores the archived save for turn and player into the watch directory, rewinds the persisted
+// state so that player is up on that turn, and announces the rollback. Saves in the watch directory from
+// later in the game are moved into the archive so they are not mistaken for the current turn. A running
+// monitor picks up the new state on its next poll.
+func Rollback(cfg *config.Config, turn int, player string) error {
+	return rollback(OSFileSystem{}, cfg, turn, player)
+}
+
+// rollback is Rollback with the watch directory on fsys. The archive is always on disk, so it is only
+// ever accessed through os.
+func rollback(fsys FileSystem, cfg *config.Config, turn int, player string) error {
+	dirPath := cfg.WatchDirectory
+	if cfg.StateFile == "" {
+		return fmt.Errorf("STATE_FILE is not set, so there is no saved state to rewind")
+	}
+	g, err := LoadGameState(cfg)
+	if err != nil {
+		return err
+	}
+
+	playerIndex := indexOfPlayer(g.players, player)
+	if playerIndex == -1 {
+		return fmt.Errorf("player %s is not in USER_MAPPINGS", player)
+	}
+	target := g.players[playerIndex]
+
+	if cfg.ArchiveDirectory == "" {
+		return fmt.Errorf("ARCHIVE_DIRECTORY is not set, so there are no archived saves to restore")
+	}
+	saveArchive := archive.New(cfg.ArchiveDirectory, g.gameName, cfg.ArchiveRetention)
+
+	entry, found, err := saveArchive.Find(turn, target.Username)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no archived save for turn %d and player %s in %s", turn, target.Username, saveArchive.Dir())
+	}
+
+	// Move later saves out of the way before restoring, so the restored save is the newest one
+	if err := setAsideLaterSaves(fsys, dirPath, g, turn, playerIndex, saveArchive.Dir()); err != nil {
+		return err
+	}
+
+	restoredPath := filepath.Join(dirPath, entry.SourceName)
+	if err := restoreFile(fsys, saveArchive.Path(entry), restoredPath); err != nil {
+		return fmt.Errorf("error restoring %s: %w", entry.SourceName, err)
+	}
+	fmt.Printf("⏪ Restored %s from the archive\n", entry.SourceName)
+
+	if err := g.rewind(turn, playerIndex, entry.SourceName); err != nil {
+		return fmt.Errorf("restored %s, but failed to rewind the state: %w", entry.SourceName, err)
+	}
+	fmt.Printf("💾 State rewound to turn %d, %s is up\n", turn, target.Username)
+
+	nextIndex, nextTurn := g.nextPlayer(playerIndex, turn)
+	if err := webhook.SendRollbackWebHook(target, g.players[nextIndex].Username, entry.SourceName, turn, nextTurn); err != nil {
+		return fmt.Errorf("rolled back, but the announcement failed: %w", err)
+	}
+	return nil
+}
+
+// rewind makes the player at playerIndex current on turn and saves the state. If the running bot saves at
+// the same moment, its state is loaded and the rewind applied again, so neither write is lost.
+func (g *GameState) rewind(turn, playerIndex int, saveFile string) error {
+	const attempts = 3
+	for attempt := 1; ; attempt++ {
+		g.mu.Lock()
+		completed, started := g.beginTurnLocked(turn, playerIndex, saveFile, false)
+		g.mu.Unlock()
+
+		err := g.Save()
+		if err == nil {
+			g.recordTurn(completed)
+			if started {
+				g.recordHandOver(completed, turn, playerIndex, saveFile)
+			}
+			return nil
+		}
+		if !errors.Is(err, state.ErrStale) || attempt == attempts {
+			return err
+		}
+		fmt.Printf("🔁 The bot saved the state at the same time, retrying the rewind\n")
+		g.reloadIfChanged()
+	}
+}
+
+// setAsideLaterSaves moves saves for turns after (turn, playerIndex) from the watch directory on fsys into a
+// rolled-back folder in the archive on disk.
+func setAsideLaterSaves(fsys FileSystem, dirPath string, g *GameState, turn, playerIndex int, archiveDir string) error {
+	files, err := fsys.ReadDir(dirPath)
+	if err != nil {
+		return fmt.Errorf("error reading directory: %w", err)
+	}
+
+	setAsideDir := filepath.Join(archiveDir, "rolled-back-"+g.now().Format("20060102-150405"))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+
+		filename := strings.ToLower(file.Name())
+		fileTurn := extractTurnNumber(filename)
+		fileIndex := indexOfPlayerInFilename(g.players, filename)
+		if !strings.HasPrefix(filename, g.gameName) || fileTurn == 0 || fileIndex == -1 {
+			continue
+		}
+		if fileTurn < turn || (fileTurn == turn && fileIndex <= playerIndex) {
+			continue
+		}
+
+		if err := os.MkdirAll(setAsideDir, 0o755); err != nil {
+			return fmt.Errorf("error creating %s: %w", setAsideDir, err)
+		}
+		// The archive may be on another filesystem, so copy the save and then remove it rather than renaming
+		src := filepath.Join(dirPath, file.Name())
+		if err := copyOut(fsys, src, filepath.Join(setAsideDir, file.Name())); err != nil {
+			return fmt.Errorf("error moving %s out of the way: %w", file.Name(), err)
+		}
+		if err := fsys.Remove(src); err != nil {
+			return fmt.Errorf("error removing %s after setting it aside: %w", file.Name(), err)
+		}
+		fmt.Printf("📦 Moved later save %s to %s\n", file.Name(), setAsideDir)
+	}
+	return nil
+}
+
+// indexOfPlayerInFilename returns the index of the first player whose username appears in a (lowercase) filename, or -1.
+func indexOfPlayerInFilename(players []userparser.UserMapping, filename string) int {
+	for i, mapping := range players {
+		if strings.Contains(filename, strings.ToLower(mapping.Username)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// copyOut copies src on fsys to dest on disk.
+func copyOut(fsys FileSystem, src, dest string) error {
+	in, err := fsys.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// restoreFile copies the archived file at src on disk to dest on fsys, replacing dest if it exists.
+func restoreFile(fsys FileSystem, src, dest string) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	return fsys.WriteFile(dest, content)
+}
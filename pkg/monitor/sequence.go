@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// sequenceProblem describes why a save doesn't fit the expected turn order.
+type sequenceProblem string
+
+const (
+	sequenceOK        sequenceProblem = ""
+	sequenceDuplicate sequenceProblem = "duplicate" // Another save for the turn that is already being played.
+	sequenceStale     sequenceProblem = "stale"     // A save for a turn that has already been played.
+	sequenceSkipped   sequenceProblem = "skipped"   // Hands the turn to a player further along than expected.
+	sequenceJump      sequenceProblem = "jump"      // Claims a turn more than a full round ahead.
+	sequenceSitting   sequenceProblem = "sitting"   // Hands the turn to a player who is sitting out.
+)
+
+// sequenceCheck is the result of checking a save against the expected next (turn, player).
+type sequenceCheck struct {
+	Problem        sequenceProblem
+	Turn           int    // Turn the save hands over, from the filename or inferred from the order.
+	ExpectedTurn   int    // Turn the next save should hand over.
+	ExpectedPlayer int    // Index of the player the next save should hand the turn to.
+	Skipped        int    // Number of players passed over, for sequenceSkipped.
+	Description    string // Human-readable explanation of the problem.
+}
+
+// checkSequence validates a save that hands turn claimedTurn (0 if the filename doesn't say) to the
+// player at playerIndex against the game state. Before any save has been processed every save is
+// accepted, since there is nothing to compare it with. A resubmission of the save that handed over
+// the current turn is also accepted.
+func checkSequence(snapshot Snapshot, filename string, claimedTurn, playerIndex int) sequenceCheck {
+	if snapshot.CurrentPlayer < 0 {
+		turn := claimedTurn
+		if turn == 0 {
+			turn = snapshot.CurrentTurn
+		}
+		return sequenceCheck{Turn: turn, ExpectedTurn: turn, ExpectedPlayer: playerIndex}
+	}
+
+	players := len(snapshot.Players)
+	expectedPlayer, expectedTurn := snapshot.NextPlayer(snapshot.CurrentPlayer, snapshot.CurrentTurn)
+	check := sequenceCheck{ExpectedTurn: expectedTurn, ExpectedPlayer: expectedPlayer}
+
+	// Without a turn in the filename, assume the save is for the player's next turn in the order
+	check.Turn = claimedTurn
+	if check.Turn == 0 {
+		switch {
+		case playerIndex == snapshot.CurrentPlayer:
+			check.Turn = snapshot.CurrentTurn
+		case playerIndex >= expectedPlayer:
+			check.Turn = expectedTurn
+		default:
+			check.Turn = expectedTurn + 1
+		}
+	}
+
+	// Compare positions in the overall sequence of hand-overs
+	position := check.Turn*players + playerIndex
+	currentPosition := snapshot.CurrentTurn*players + snapshot.CurrentPlayer
+	expectedPosition := expectedTurn*players + expectedPlayer
+	player := snapshot.Players[playerIndex].Username
+	expected := snapshot.Players[expectedPlayer].Username
+
+	switch {
+	case position == expectedPosition:
+		// The save everyone is waiting for
+	case snapshot.SittingOut[strings.ToLower(player)] && position != currentPosition:
+		check.Problem = sequenceSitting
+		check.Description = fmt.Sprintf("the save hands turn %d to %s, who is sitting out", check.Turn, player)
+	case position == currentPosition:
+		if strings.EqualFold(filename, snapshot.LastSave) {
+			break // Resubmission of the current save
+		}
+		check.Problem = sequenceDuplicate
+		check.Description = fmt.Sprintf("%s already has turn %d from %s, this is a second save for the same turn",
+			player, check.Turn, snapshot.LastSave)
+	case position < currentPosition:
+		check.Problem = sequenceStale
+		check.Description = fmt.Sprintf("turn %d for %s has already been played, the game is on turn %d (%s)",
+			check.Turn, player, snapshot.CurrentTurn, snapshot.Players[snapshot.CurrentPlayer].Username)
+	case position-expectedPosition < players:
+		check.Problem = sequenceSkipped
+		var skipped []string
+		for i := 0; i < position-expectedPosition; i++ {
+			username := snapshot.Players[(expectedPlayer+i)%players].Username
+			if !snapshot.SittingOut[strings.ToLower(username)] {
+				skipped = append(skipped, username)
+			}
+		}
+		check.Skipped = len(skipped)
+		check.Description = fmt.Sprintf("the save hands turn %d to %s, skipping %s",
+			check.Turn, player, strings.Join(skipped, ", "))
+	default:
+		check.Problem = sequenceJump
+		check.Description = fmt.Sprintf("the save claims turn %d for %s, but the next save should be turn %d for %s",
+			check.Turn, player, expectedTurn, expected)
+	}
+	return check
+}
+
+// reportSequenceProblem tells the game about a save that doesn't fit the turn order. The turn is not
+// handed over; the group decides whether to remove the file or pass over players on purpose.
+func reportSequenceProblem(name string, check sequenceCheck, state *GameState) {
+	snapshot := state.Snapshot()
+	expected := snapshot.Players[check.ExpectedPlayer].Username
+	expectedName := state.saveFileName(check.ExpectedTurn, expected) + filepath.Ext(name)
+
+	fmt.Printf("🧭 Out-of-order save %s (%s): %s. Waiting for %s\n", name, check.Problem, check.Description, expectedName)
+	if err := webhook.SendSequenceWebHook(name, check.Description, expectedName); err != nil {
+		fmt.Printf("❌ Failed to report out-of-order save %s: %v\n", name, err)
+	}
+}
@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+func TestCheckSequence(t *testing.T) {
+	players := []userparser.UserMapping{
+		{Order: 1, Username: "alice"},
+		{Order: 2, Username: "bob"},
+		{Order: 3, Username: "carol"},
+	}
+	// Alice has handed turn 1 to bob, so the next save should hand it to carol
+	bobsTurn := Snapshot{Players: players, CurrentTurn: 1, CurrentPlayer: 1, LastSave: "pbem1_turn1_bob.se"}
+	aliceSittingOut := bobsTurn
+	aliceSittingOut.SittingOut = map[string]bool{"alice": true}
+	carolSittingOut := bobsTurn
+	carolSittingOut.SittingOut = map[string]bool{"carol": true}
+
+	tests := []struct {
+		name        string
+		snapshot    Snapshot
+		filename    string
+		claimedTurn int
+		player      int
+		want        sequenceProblem
+		wantTurn    int
+		wantSkipped int
+	}{
+		{"first save", Snapshot{Players: players, CurrentTurn: 1, CurrentPlayer: -1}, "pbem1_turn3_carol.se", 3, 2, sequenceOK, 3, 0},
+		{"first save without a turn", Snapshot{Players: players, CurrentTurn: 1, CurrentPlayer: -1}, "pbem1_carol.se", 0, 2, sequenceOK, 1, 0},
+		{"expected save", bobsTurn, "pbem1_turn1_carol.se", 1, 2, sequenceOK, 1, 0},
+		{"expected save without a turn", bobsTurn, "pbem1_carol.se", 0, 2, sequenceOK, 1, 0},
+		{"resubmission", bobsTurn, "PBEM1_turn1_bob.se", 1, 1, sequenceOK, 1, 0},
+		{"second save for the current turn", bobsTurn, "pbem1_turn1_bob_v2.se", 1, 1, sequenceDuplicate, 1, 0},
+		{"turn already played", bobsTurn, "pbem1_turn1_alice.se", 1, 0, sequenceStale, 1, 0},
+		{"one player skipped", bobsTurn, "pbem1_turn2_alice.se", 2, 0, sequenceSkipped, 2, 1},
+		{"without a turn, skipping a player", bobsTurn, "pbem1_alice.se", 0, 0, sequenceSkipped, 2, 1},
+		{"more than a round ahead", bobsTurn, "pbem1_turn3_alice.se", 3, 0, sequenceJump, 3, 0},
+		{"wraps to the next turn past a player sitting out", carolSittingOut, "pbem1_turn2_alice.se", 2, 0, sequenceOK, 2, 0},
+		{"to a player sitting out", carolSittingOut, "pbem1_turn1_carol.se", 1, 2, sequenceSitting, 1, 0},
+		{"players sitting out aren't counted as skipped", aliceSittingOut, "pbem1_turn2_bob.se", 2, 1, sequenceSkipped, 2, 1},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check := checkSequence(test.snapshot, test.filename, test.claimedTurn, test.player)
+			if check.Problem != test.want || check.Turn != test.wantTurn || check.Skipped != test.wantSkipped {
+				t.Errorf("checkSequence() = problem %q, turn %d, %d skipped (%s), want problem %q, turn %d, %d skipped",
+					check.Problem, check.Turn, check.Skipped, check.Description, test.want, test.wantTurn, test.wantSkipped)
+			}
+		})
+	}
+}
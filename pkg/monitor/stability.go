@@ -0,0 +1,87 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// newFileTrackingInfo starts tracking a file seen for the first time at now.
+func newFileTrackingInfo(file fs.DirEntry, now int64) *FileTrackingInfo {
+	info := &FileTrackingInfo{
+		FirstSeen:   now,
+		StableSince: now,
+		Processed:   false,
+	}
+	if fileInfo, err := file.Info(); err == nil {
+		info.Size = fileInfo.Size()
+		info.ModTime = fileInfo.ModTime().UnixMilli()
+	}
+	return info
+}
+
+// updateStability compares a file's current size, modification time and (optionally) content hash
+// with the previous poll. Any change restarts the debounce window. If the file had already been
+// processed and its content changed, it was overwritten in place and is reopened for processing
+// as a resubmission.
+func updateStability(fsys FileSystem, info *FileTrackingInfo, filename, path string, file fs.DirEntry, now int64, hashFiles bool) {
+	fileInfo, err := file.Info()
+	if err != nil {
+		fmt.Printf("Error getting file info for %s: %v\n", filename, err)
+		return
+	}
+
+	size := fileInfo.Size()
+	modTime := fileInfo.ModTime().UnixMilli()
+	metadataChanged := size != info.Size || modTime != info.ModTime
+	info.Size = size
+	info.ModTime = modTime
+
+	// Processed files are only hashed when their metadata changes, to avoid re-reading every save on every poll
+	if !hashFiles || (info.Processed && !metadataChanged) {
+		if metadataChanged {
+			markChanged(info, filename, now)
+		}
+		return
+	}
+
+	hash, err := hashFile(fsys, path)
+	if err != nil {
+		fmt.Printf("Error hashing %s: %v\n", filename, err)
+		return
+	}
+	previousHash := info.Hash
+	info.Hash = hash
+
+	// An unchanged hash means only the metadata was touched; an empty previous hash means we have
+	// nothing to compare against, so fall back to the metadata.
+	if hash != previousHash && (previousHash != "" || metadataChanged) {
+		markChanged(info, filename, now)
+	}
+}
+
+// markChanged restarts the debounce window for a file whose content changed.
+func markChanged(info *FileTrackingInfo, filename string, now int64) {
+	info.StableSince = now
+	if info.Processed {
+		fmt.Printf("🔁 Processed save %s was overwritten in place, treating it as a resubmission\n", filename)
+		info.Processed = false
+	}
+}
+
+// hashFile returns the hex encoded SHA-256 of a file's content.
+func hashFile(fsys FileSystem, path string) (string, error) {
+	f, err := fsys.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,542 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/jsonl"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/state"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// ErrNoCurrentPlayer is returned by actions that need a current player before any save has been processed.
+var ErrNoCurrentPlayer = errors.New("no save has been processed yet, so there is no current player")
+
+// maxHistory is the number of past turns kept in memory for the history view.
+const maxHistory = 50
+
+// TurnEvent records a single hand-over of the turn to a player.
+type TurnEvent struct {
+	Turn      int           // Turn number the player was asked to play.
+	Player    string        // Username of the player who received the turn.
+	SaveFile  string        // Save file that handed over the turn (empty when skipped).
+	StartedAt time.Time     // When the player was notified.
+	Skipped   bool          // True if the previous player was skipped to get here.
+	Paused    time.Duration // Time the turn spent paused, filled in when the next turn starts.
+}
+
+// PendingRename is a request for a player to rename a misnamed save, awaiting the corrected file.
+type PendingRename struct {
+	Original  string    // The misnamed file.
+	Expected  string    // The exact name the save should have.
+	Saver     string    // Username of the player asked to rename it.
+	Requested time.Time // When the player was asked.
+}
+
+// Snapshot is a point-in-time copy of the game state that is safe to read without locking.
+type Snapshot struct {
+	GameName       string
+	Players        []userparser.UserMapping
+	CurrentTurn    int
+	CurrentPlayer  int // Index into Players, or -1 if no save has been processed yet.
+	LastSave       string
+	TurnStarted    time.Time
+	History        []TurnEvent
+	PendingRename  *PendingRename  // Outstanding rename request, or nil.
+	SittingOut     map[string]bool // Lowercase usernames of players passed over until reinstated.
+	Paused         bool
+	PausedAt       time.Time     // When the game was paused, if it is.
+	PausedUntil    time.Time     // When the game resumes by itself, zero if not set.
+	PauseReason    string        // Why the game is paused.
+	TurnPaused     time.Duration // Time the current turn spent paused before the current pause.
+	Deadline       time.Duration // Time allowed for a turn, zero if there is no deadline.
+	FinalWarning   time.Duration // How long before the deadline the final warning is sent.
+	DeadlineWarned bool          // Whether the current player has had the final warning.
+	DeadlineMissed bool          // Whether the current player's deadline has passed and been dealt with.
+	NextReminder   time.Time     // When the next file age reminder is due, zero if not yet known.
+}
+
+// Waiting returns how long the current player has had the turn, not counting time the game was paused.
+func (s Snapshot) Waiting(now time.Time) time.Duration {
+	if s.TurnStarted.IsZero() {
+		return 0
+	}
+	return now.Sub(s.TurnStarted) - s.TurnPaused - ongoingPause(s.Paused, s.PausedAt, s.TurnStarted, now)
+}
+
+// DueAt returns when the current turn must be played by, if there is a deadline. While the game is
+// paused the due time moves forward, as paused time doesn't count towards the deadline.
+func (s Snapshot) DueAt(now time.Time) (time.Time, bool) {
+	if s.Deadline <= 0 || s.TurnStarted.IsZero() {
+		return time.Time{}, false
+	}
+	return now.Add(s.Deadline - s.Waiting(now)), true
+}
+
+// ongoingPause returns how much of the current turn has been spent in the pause that is still going on.
+func ongoingPause(paused bool, pausedAt, turnStarted, now time.Time) time.Duration {
+	if !paused {
+		return 0
+	}
+	if pausedAt.Before(turnStarted) {
+		pausedAt = turnStarted
+	}
+	return now.Sub(pausedAt)
+}
+
+// NextPlayer returns the index of the next player after index who is not sitting out, and the turn
+// they will play.
+func (s Snapshot) NextPlayer(index, turn int) (int, int) {
+	return nextActivePlayer(s.Players, s.SittingOut, index, turn)
+}
+
+// CurrentMapping returns the mapping of the player whose turn it is, if known.
+func (s Snapshot) CurrentMapping() (userparser.UserMapping, bool) {
+	if s.CurrentPlayer < 0 || s.CurrentPlayer >= len(s.Players) {
+		return userparser.UserMapping{}, false
+	}
+	return s.Players[s.CurrentPlayer], true
+}
+
+// GameState holds the turn-tracking state shared between the directory watcher
+// and anything else that inspects or drives the game, such as the Discord bot.
+type GameState struct {
+	mu               sync.RWMutex
+	gameName         string // Lowercase, for matching save files.
+	savePrefix       string // The game name as configured, which starts the save names players are asked for.
+	players          []userparser.UserMapping
+	currentTurn      int
+	currentPlayer    int
+	lastSave         string
+	turnStarted      time.Time
+	history          []TurnEvent
+	pendingRename    *PendingRename
+	sittingOut       map[string]bool // Lowercase usernames of players passed over until reinstated.
+	skips            map[string]int  // Times each player (by lowercase username) has been skipped.
+	paused           bool
+	pausedAt         time.Time
+	pausedUntil      time.Time
+	pauseReason      string
+	turnPaused       time.Duration            // Time the current turn spent paused before the current pause.
+	turnReminders    int                      // Reminders sent to the current player during this turn.
+	deadline         deadlinePolicy           // How long players have to take their turn.
+	deadlineWarned   bool                     // Whether the current player has had the final warning before the deadline.
+	deadlineMissed   bool                     // Whether the current player's deadline has passed and been dealt with.
+	nextReminder     time.Time                // When the next file age reminder is due, zero until the latest save has been checked.
+	turnLog          *jsonl.Log[history.Turn] // Permanent record of completed turns, or nil to keep none.
+	eventLog         *jsonl.Log[events.Event] // Where game events are recorded for feeds, or nil to record none.
+	lastSummary      time.Time                // When the last turn time summary was posted.
+	lastDigest       time.Time                // When the last scheduled digest was posted.
+	lastRoundSummary int                      // Last round a summary was posted for.
+	lastPoll         time.Time                // When the watch directory was last polled.
+	pollErr          error                    // Error from the last poll, or nil if it succeeded.
+	clock            Clock                    // Source of the current time, or nil for the system clock.
+	store            *state.Store             // Where the state is persisted, or nil to keep it in memory only.
+}
+
+// NewGameState creates the state for a game with the given turn order.
+func NewGameState(gameName string, players []userparser.UserMapping) *GameState {
+	return &GameState{
+		gameName:      strings.ToLower(gameName),
+		savePrefix:    gameName,
+		players:       players,
+		currentTurn:   1,
+		currentPlayer: -1,
+		sittingOut:    make(map[string]bool),
+		skips:         make(map[string]int),
+	}
+}
+
+// LoadGameState builds the game state for the game and players in cfg. If a state file is configured
+// (STATE_FILE), the state is saved to it and the current turn and player restored from it; otherwise
+// the state is only kept in memory. Completed turns and game events are recorded in HISTORY_FILE and
+// EVENT_LOG if they are set.
+func LoadGameState(cfg *config.Config) (*GameState, error) {
+	g := NewGameState(cfg.GameName, cfg.Players)
+	if cfg.StateFile != "" {
+		g.store = state.NewStore(cfg.StateFile)
+		data, err := g.store.Load()
+		if err != nil {
+			return nil, err
+		}
+		if data != nil {
+			g.restore(data)
+			fmt.Printf("💾 Restored state from %s: turn %d\n", g.store.Path(), g.currentTurn)
+		}
+	}
+
+	if cfg.HistoryFile != "" {
+		g.turnLog = jsonl.New[history.Turn](cfg.HistoryFile)
+	}
+	if cfg.EventLog != "" {
+		g.eventLog = jsonl.New[events.Event](cfg.EventLog)
+	}
+	if err := g.loadHistory(); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// restore replaces the turn tracking with persisted data.
+func (g *GameState) restore(data *state.Data) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.currentTurn = max(data.CurrentTurn, 1)
+	g.currentPlayer = indexOfPlayer(g.players, data.CurrentPlayer)
+	g.lastSave = data.LastSave
+	g.turnStarted = data.TurnStarted
+	g.paused = data.Paused
+	g.pausedAt = data.PausedAt
+	g.pausedUntil = data.PausedUntil
+	g.pauseReason = data.PauseReason
+	g.turnPaused = data.TurnPaused
+	g.turnReminders = data.TurnReminders
+	g.deadlineWarned = data.DeadlineWarned
+	g.deadlineMissed = data.DeadlineMissed
+	g.lastSummary = data.LastSummary
+	g.lastDigest = data.LastDigest
+	g.lastRoundSummary = data.LastRoundSummary
+	g.pendingRename = nil
+	if pending := data.PendingRename; pending != nil {
+		g.pendingRename = &PendingRename{
+			Original:  pending.Original,
+			Expected:  pending.Expected,
+			Saver:     pending.Saver,
+			Requested: pending.Requested,
+		}
+	}
+	g.sittingOut = make(map[string]bool)
+	for _, username := range data.SittingOut {
+		g.sittingOut[strings.ToLower(username)] = true
+	}
+	g.skips = make(map[string]int)
+	for username, count := range data.Skips {
+		g.skips[strings.ToLower(username)] = count
+	}
+	if data.CurrentPlayer != "" && g.currentPlayer == -1 {
+		fmt.Printf("⚠️ Persisted current player %s is not in USER_MAPPINGS, waiting for the next save\n", data.CurrentPlayer)
+	}
+}
+
+// persist writes the current turn tracking to the state file, if one is configured.
+func (g *GameState) persist() {
+	err := g.Save()
+	switch {
+	case errors.Is(err, state.ErrStale):
+		// Another process, such as a rollback, wrote the file last; its state wins on the next poll
+		fmt.Printf("⚠️ Not saving state: %v, it will be loaded on the next poll\n", err)
+	case err != nil:
+		fmt.Printf("❌ Failed to save state: %v\n", err)
+	}
+}
+
+// Save writes the state to the state file, if there is one. The state is saved whenever it changes,
+// so this is only needed to make sure it is on disk, such as when shutting down.
+func (g *GameState) Save() error {
+	if g.store == nil {
+		return nil
+	}
+
+	g.mu.RLock()
+	data := &state.Data{
+		CurrentTurn:      g.currentTurn,
+		LastSave:         g.lastSave,
+		TurnStarted:      g.turnStarted,
+		Paused:           g.paused,
+		PausedAt:         g.pausedAt,
+		PausedUntil:      g.pausedUntil,
+		PauseReason:      g.pauseReason,
+		TurnPaused:       g.turnPaused,
+		TurnReminders:    g.turnReminders,
+		DeadlineWarned:   g.deadlineWarned,
+		DeadlineMissed:   g.deadlineMissed,
+		LastSummary:      g.lastSummary,
+		LastDigest:       g.lastDigest,
+		LastRoundSummary: g.lastRoundSummary,
+		Skips:            maps.Clone(g.skips),
+	}
+	if pending := g.pendingRename; pending != nil {
+		data.PendingRename = &state.Rename{
+			Original:  pending.Original,
+			Expected:  pending.Expected,
+			Saver:     pending.Saver,
+			Requested: pending.Requested,
+		}
+	}
+	for _, player := range g.players {
+		if g.sittingOut[strings.ToLower(player.Username)] {
+			data.SittingOut = append(data.SittingOut, player.Username)
+		}
+	}
+	if g.currentPlayer >= 0 {
+		data.CurrentPlayer = g.players[g.currentPlayer].Username
+	}
+	g.mu.RUnlock()
+
+	return g.store.Save(data)
+}
+
+// reloadIfChanged adopts state written by another process (such as the rollback command).
+// It returns true if the state changed.
+func (g *GameState) reloadIfChanged() bool {
+	if g.store == nil {
+		return false
+	}
+
+	data, err := g.store.LoadIfChanged()
+	if err != nil {
+		fmt.Printf("❌ Failed to reload state: %v\n", err)
+		return false
+	}
+	if data == nil {
+		return false
+	}
+
+	g.restore(data)
+	fmt.Printf("💾 State file changed externally, now on turn %d (%s)\n", data.CurrentTurn, data.CurrentPlayer)
+	return true
+}
+
+// indexOfPlayer returns the index of the player with the given username (case-insensitive), or -1.
+func indexOfPlayer(players []userparser.UserMapping, username string) int {
+	for i, player := range players {
+		if strings.EqualFold(player.Username, username) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Snapshot returns a copy of the current state.
+func (g *GameState) Snapshot() Snapshot {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	return Snapshot{
+		GameName:       g.gameName,
+		Players:        append([]userparser.UserMapping(nil), g.players...),
+		CurrentTurn:    g.currentTurn,
+		CurrentPlayer:  g.currentPlayer,
+		LastSave:       g.lastSave,
+		TurnStarted:    g.turnStarted,
+		History:        append([]TurnEvent(nil), g.history...),
+		PendingRename:  g.pendingRename,
+		SittingOut:     maps.Clone(g.sittingOut),
+		Paused:         g.paused,
+		PausedAt:       g.pausedAt,
+		PausedUntil:    g.pausedUntil,
+		PauseReason:    g.pauseReason,
+		TurnPaused:     g.turnPaused,
+		Deadline:       g.deadline.limit,
+		FinalWarning:   g.deadline.warning,
+		DeadlineWarned: g.deadlineWarned,
+		DeadlineMissed: g.deadlineMissed,
+		NextReminder:   g.nextReminder,
+	}
+}
+
+// setPendingRename records (or clears, with nil) an outstanding rename request. It is saved with the rest
+// of the state, so the status command and a restarted bot see it too.
+func (g *GameState) setPendingRename(pending *PendingRename) {
+	g.mu.Lock()
+	g.pendingRename = pending
+	g.mu.Unlock()
+	g.persist()
+}
+
+// Players returns the turn order.
+func (g *GameState) Players() []userparser.UserMapping {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return append([]userparser.UserMapping(nil), g.players...)
+}
+
+// SetPlayers replaces the turn order, such as when the configuration is reloaded. The current player
+// keeps the turn if they are still in the game; if not, the next save decides whose turn it is.
+func (g *GameState) SetPlayers(players []userparser.UserMapping) {
+	g.mu.Lock()
+	current := ""
+	if g.currentPlayer >= 0 && g.currentPlayer < len(g.players) {
+		current = g.players[g.currentPlayer].Username
+	}
+	g.players = append([]userparser.UserMapping(nil), players...)
+	g.currentPlayer = indexOfPlayer(g.players, current)
+	g.mu.Unlock()
+
+	if current != "" && indexOfPlayer(players, current) == -1 {
+		fmt.Printf("⚠️ %s is no longer in the turn order, the next save will decide whose turn it is\n", current)
+	}
+	g.persist()
+}
+
+// GameName returns the name of the game.
+func (g *GameState) GameName() string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.gameName
+}
+
+// Turn returns the current turn number.
+func (g *GameState) Turn() int {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.currentTurn
+}
+
+// beginTurn records that the player at playerIndex has been handed turn by saveFile.
+func (g *GameState) beginTurn(turn, playerIndex int, saveFile string, skipped bool) {
+	g.mu.Lock()
+	completed, started := g.beginTurnLocked(turn, playerIndex, saveFile, skipped)
+	g.mu.Unlock()
+	g.persist()
+	g.recordTurn(completed)
+	if started {
+		g.recordHandOver(completed, turn, playerIndex, saveFile)
+	}
+}
+
+// beginTurnLocked updates the state for a new turn, reporting whether one was started (rather than the
+// current one being resubmitted). If the turn passed to the next player in the order, the turn that
+// just ended is also returned so it can be recorded in the history file.
+func (g *GameState) beginTurnLocked(turn, playerIndex int, saveFile string, skipped bool) (*history.Turn, bool) {
+	now := nowFrom(g.clock)
+
+	// A resubmission of the save that started the current turn doesn't start a new one
+	if g.currentPlayer == playerIndex && g.currentTurn == turn && !skipped {
+		if saveFile != "" {
+			g.lastSave = saveFile
+		}
+		if len(g.history) > 0 {
+			g.history[len(g.history)-1].SaveFile = saveFile
+		}
+		return nil, false
+	}
+	completed := g.completedTurnLocked(turn, playerIndex, saveFile, skipped, now)
+	if skipped && g.currentPlayer >= 0 {
+		g.skips[strings.ToLower(g.players[g.currentPlayer].Username)]++
+	}
+
+	// Close off the paused time of the turn that is ending
+	if len(g.history) > 0 {
+		g.history[len(g.history)-1].Paused = g.turnPaused + ongoingPause(g.paused, g.pausedAt, g.turnStarted, now)
+	}
+	g.turnPaused = 0
+	g.turnReminders = 0
+	g.deadlineWarned = false
+	g.deadlineMissed = false
+	g.nextReminder = time.Time{}
+
+	g.currentTurn = turn
+	g.currentPlayer = playerIndex
+	if saveFile != "" {
+		g.lastSave = saveFile
+	}
+	g.turnStarted = now
+
+	g.history = append(g.history, TurnEvent{
+		Turn:      turn,
+		Player:    g.players[playerIndex].Username,
+		SaveFile:  saveFile,
+		StartedAt: now,
+		Skipped:   skipped,
+	})
+	if len(g.history) > maxHistory {
+		g.history = g.history[len(g.history)-maxHistory:]
+	}
+	return completed, true
+}
+
+// nextPlayer returns the index of the next player after index who is not sitting out and the turn
+// they will play, wrapping to the next turn after the last player in the order.
+func (g *GameState) nextPlayer(index, turn int) (int, int) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.nextPlayerLocked(index, turn)
+}
+
+func (g *GameState) nextPlayerLocked(index, turn int) (int, int) {
+	return nextActivePlayer(g.players, g.sittingOut, index, turn)
+}
+
+// nextActivePlayer walks the turn order from index to the next player who is not sitting out.
+// If everyone is sitting out, the player directly after index is returned.
+func nextActivePlayer(players []userparser.UserMapping, sittingOut map[string]bool, index, turn int) (int, int) {
+	nextIndex, nextTurn := index, turn
+	for range players {
+		nextIndex = (nextIndex + 1) % len(players)
+		if nextIndex == 0 {
+			nextTurn++
+		}
+		if !sittingOut[strings.ToLower(players[nextIndex].Username)] {
+			return nextIndex, nextTurn
+		}
+	}
+
+	nextIndex = (index + 1) % len(players)
+	if nextIndex == 0 {
+		return nextIndex, turn + 1
+	}
+	return nextIndex, turn
+}
+
+// SkipCurrentPlayer passes the turn from the current player to the next one and notifies them.
+// The next player is told to continue from the latest save. It returns ErrGamePaused while the game
+// is paused. If the state changed but the notification failed, the skipped and next players are
+// returned along with the error.
+func (g *GameState) SkipCurrentPlayer() (skipped, next userparser.UserMapping, err error) {
+	g.mu.Lock()
+	if g.currentPlayer < 0 {
+		g.mu.Unlock()
+		return skipped, next, ErrNoCurrentPlayer
+	}
+	if g.paused {
+		g.mu.Unlock()
+		return skipped, next, ErrGamePaused
+	}
+
+	skipped = g.players[g.currentPlayer]
+	nextIndex, nextTurn := g.nextPlayerLocked(g.currentPlayer, g.currentTurn)
+	next = g.players[nextIndex]
+	afterNextIndex, saveTurn := g.nextPlayerLocked(nextIndex, nextTurn)
+	afterNext := g.players[afterNextIndex]
+	lastSave := g.lastSave
+
+	completed, _ := g.beginTurnLocked(nextTurn, nextIndex, "", true)
+	g.mu.Unlock()
+	g.persist()
+	g.recordTurn(completed)
+	g.recordHandOver(completed, nextTurn, nextIndex, "")
+
+	fmt.Printf("⏭️ Skipped %s, turn %d passes to %s\n", skipped.Username, nextTurn, next.Username)
+	err = webhook.SendSkipWebHook(skipped.Username, next, afterNext.Username, lastSave, saveTurn)
+	return skipped, next, err
+}
+
+// RemindCurrentPlayer pings the current player with how long they have had the turn.
+func (g *GameState) RemindCurrentPlayer() (userparser.UserMapping, error) {
+	snapshot := g.Snapshot()
+	current, ok := snapshot.CurrentMapping()
+	if !ok {
+		return current, ErrNoCurrentPlayer
+	}
+
+	if snapshot.Paused {
+		return current, ErrGamePaused
+	}
+
+	fmt.Printf("⏰ Reminding %s about turn %d\n", current.Username, snapshot.CurrentTurn)
+	err := webhook.SendReminderWebHook(current, snapshot.CurrentTurn, snapshot.Waiting(g.now()))
+	if err == nil {
+		g.noteReminder()
+	}
+	return current, err
+}
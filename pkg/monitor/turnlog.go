@@ -0,0 +1,155 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// completedTurnLocked returns the record of the current turn if handing turn to playerIndex completes it,
+// which is the case when the turn passes to the next player in the order. Rollbacks and turns set by an
+// admin jump elsewhere and don't complete the current turn.
+func (g *GameState) completedTurnLocked(turn, playerIndex int, saveFile string, skipped bool, now time.Time) *history.Turn {
+	if g.currentPlayer < 0 || len(g.history) == 0 {
+		return nil
+	}
+	if nextIndex, nextTurn := g.nextPlayerLocked(g.currentPlayer, g.currentTurn); nextIndex != playerIndex || nextTurn != turn {
+		return nil
+	}
+
+	current := g.history[len(g.history)-1]
+	return &history.Turn{
+		Game:          g.gameName,
+		Turn:          current.Turn,
+		Player:        current.Player,
+		NotifiedAt:    current.StartedAt,
+		SubmittedAt:   now,
+		ReceivedFile:  current.SaveFile,
+		SubmittedFile: saveFile,
+		Paused:        g.turnPaused + ongoingPause(g.paused, g.pausedAt, g.turnStarted, now),
+		Reminders:     g.turnReminders,
+		Skipped:       skipped,
+	}
+}
+
+// recordTurn appends a completed turn to the history file, if there is one.
+func (g *GameState) recordTurn(turn *history.Turn) {
+	if turn == nil || g.turnLog == nil {
+		return
+	}
+	if err := g.turnLog.Append(*turn); err != nil {
+		fmt.Printf("❌ Failed to record turn %d for %s: %v\n", turn.Turn, turn.Player, err)
+	}
+}
+
+// loadHistory fills the in-memory turn history from the history file, followed by the current turn.
+func (g *GameState) loadHistory() error {
+	turns, err := g.TurnHistory()
+	if err != nil {
+		return err
+	}
+	if len(turns) > maxHistory {
+		turns = turns[len(turns)-maxHistory:]
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.history = g.history[:0]
+	for i, turn := range turns {
+		g.history = append(g.history, TurnEvent{
+			Turn:      turn.Turn,
+			Player:    turn.Player,
+			SaveFile:  turn.ReceivedFile,
+			StartedAt: turn.NotifiedAt,
+			Skipped:   i > 0 && turns[i-1].Skipped,
+			Paused:    turn.Paused,
+		})
+	}
+	if g.currentPlayer >= 0 {
+		g.history = append(g.history, TurnEvent{
+			Turn:      g.currentTurn,
+			Player:    g.players[g.currentPlayer].Username,
+			SaveFile:  g.lastSave,
+			StartedAt: g.turnStarted,
+			Skipped:   len(turns) > 0 && turns[len(turns)-1].Skipped,
+		})
+	}
+	return nil
+}
+
+// setNextReminder records when the next file age reminder is due, for the status API.
+func (g *GameState) setNextReminder(at time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.nextReminder = at
+}
+
+// noteReminder counts a reminder sent to the current player, for the turn statistics.
+func (g *GameState) noteReminder() {
+	g.mu.Lock()
+	g.turnReminders++
+	g.mu.Unlock()
+	g.persist()
+}
+
+// keepsHistory reports whether completed turns are being recorded in a turn history.
+func (g *GameState) keepsHistory() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.turnLog != nil
+}
+
+// TurnHistory returns every completed turn recorded for the game, oldest first.
+func (g *GameState) TurnHistory() ([]history.Turn, error) {
+	if g.turnLog == nil {
+		return nil, nil
+	}
+	return g.turnLog.Read(history.ForGame(g.gameName))
+}
+
+// sendSummaryIfDue posts a summary of the turns completed since the last one, once interval has
+// passed. The first summary is due an interval after the bot first starts with summaries enabled.
+func (g *GameState) sendSummaryIfDue(interval time.Duration) {
+	if interval <= 0 || g.turnLog == nil {
+		return
+	}
+
+	now := g.now()
+	g.mu.Lock()
+	since := g.lastSummary
+	if since.IsZero() {
+		g.lastSummary = now
+	}
+	g.mu.Unlock()
+	if since.IsZero() {
+		g.persist()
+		return
+	}
+	if now.Sub(since) < interval {
+		return
+	}
+
+	turns, err := g.TurnHistory()
+	if err != nil {
+		fmt.Printf("❌ Failed to read turn history for the summary: %v\n", err)
+		return
+	}
+	var players []string
+	for _, player := range g.Players() {
+		players = append(players, player.Username)
+	}
+
+	fmt.Printf("📊 Posting the turn summary since %s\n", since.Format(time.DateTime))
+	if err := webhook.SendStatsWebHook(history.Stats(turns, players, since), since); err != nil {
+		fmt.Printf("❌ Failed to post the turn summary: %v\n", err)
+	}
+
+	// Wait for the next interval even if posting failed, rather than retrying on every poll
+	g.mu.Lock()
+	g.lastSummary = now
+	g.mu.Unlock()
+	g.persist()
+}
@@ -0,0 +1,211 @@
+// Package savefile inspects Shadow Empire save files.
+//
+// The save format is not documented, so inspection relies on properties any save must have:
+// a plausible size, a recognisable container format that decodes to the end without error,
+// and (best effort) text embedded in the decoded content such as player names and turn markers.
+package savefile
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Format identifies the container format of a save file.
+type Format string
+
+const (
+	FormatUnknown Format = "unknown" // No known signature; treated as uncompressed data.
+	FormatGzip    Format = "gzip"
+	FormatZlib    Format = "zlib"
+	FormatZip     Format = "zip"
+	FormatDotNet  Format = "dotnet" // .NET BinaryFormatter stream.
+)
+
+// DefaultMinSize is the smallest file accepted as a save when no other minimum is configured.
+const DefaultMinSize = 1024
+
+// scanLimit is how much decoded content is kept for metadata extraction.
+const scanLimit = 4 << 20
+
+// dotNetHeader is the SerializedStreamHeader record that starts a .NET BinaryFormatter stream.
+var dotNetHeader = []byte{0x00, 0x01, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF}
+
+// turnPattern finds turn markers such as "Turn 12" or "turn: 12" in decoded text.
+var turnPattern = regexp.MustCompile(`(?i)\bturn\W{0,3}(\d{1,4})\b`)
+
+// Info describes what could be read from a save file.
+type Info struct {
+	Size      int64  // Size of the file in bytes.
+	Format    Format // Container format detected from the file signature.
+	Truncated bool   // True if the compressed stream or archive ended early or failed to decode.
+	Turn      int    // Turn number embedded in the content, or 0 if none was found.
+
+	text string // Decoded content (up to scanLimit) with UTF-16 and NUL padding flattened, lowercased.
+}
+
+// ContainsName reports whether a player name appears in the decoded content.
+func (i *Info) ContainsName(name string) bool {
+	return name != "" && strings.Contains(i.text, strings.ToLower(name))
+}
+
+// Readable reports whether any text could be extracted from the content.
+func (i *Info) Readable() bool {
+	return i.text != ""
+}
+
+// Inspect reads an open save file and returns what could be determined about it.
+// An error is only returned if the file cannot be read at all.
+func Inspect(f fs.File) (*Info, error) {
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &Info{Size: stat.Size(), Format: FormatUnknown}
+	reader := bufio.NewReader(f)
+	signature, _ := reader.Peek(len(dotNetHeader))
+	info.Format = detectFormat(signature)
+
+	var content []byte
+	switch info.Format {
+	case FormatGzip:
+		content, info.Truncated = decode(func() (io.ReadCloser, error) { return gzip.NewReader(reader) })
+	case FormatZlib:
+		content, info.Truncated = decode(func() (io.ReadCloser, error) { return zlib.NewReader(reader) })
+	case FormatZip:
+		// Zip archives are read from the end, so files that can't seek are read into memory first
+		archive, ok := f.(io.ReaderAt)
+		if !ok {
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				return nil, err
+			}
+			archive = bytes.NewReader(data)
+		}
+		content, info.Truncated = readZip(archive, stat.Size())
+	default:
+		content, err = io.ReadAll(io.LimitReader(reader, scanLimit))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	info.text = flattenText(content)
+	if matches := turnPattern.FindStringSubmatch(info.text); matches != nil {
+		info.Turn, _ = strconv.Atoi(matches[1])
+	}
+	return info, nil
+}
+
+// detectFormat identifies the container format from the first bytes of a file.
+func detectFormat(signature []byte) Format {
+	switch {
+	case len(signature) >= 2 && signature[0] == 0x1F && signature[1] == 0x8B:
+		return FormatGzip
+	case len(signature) >= 4 && bytes.Equal(signature[:4], []byte("PK\x03\x04")):
+		return FormatZip
+	case len(signature) >= 2 && signature[0] == 0x78 && (uint16(signature[0])<<8|uint16(signature[1]))%31 == 0:
+		return FormatZlib
+	case bytes.HasPrefix(signature, dotNetHeader):
+		return FormatDotNet
+	}
+	return FormatUnknown
+}
+
+// decode reads a compressed stream to the end, keeping the first scanLimit bytes.
+// It reports the stream as truncated if it cannot be decoded completely.
+func decode(open func() (io.ReadCloser, error)) ([]byte, bool) {
+	stream, err := open()
+	if err != nil {
+		return nil, true
+	}
+	defer stream.Close()
+
+	var kept bytes.Buffer
+	if _, err := io.CopyN(&kept, stream, scanLimit); err != nil && !errors.Is(err, io.EOF) {
+		return kept.Bytes(), true
+	}
+	if _, err := io.Copy(io.Discard, stream); err != nil {
+		return kept.Bytes(), true
+	}
+	return kept.Bytes(), false
+}
+
+// readZip opens a zip archive and decodes its largest entry. A missing or damaged central
+// directory (the usual result of a partial upload) is reported as truncation.
+func readZip(f io.ReaderAt, size int64) ([]byte, bool) {
+	archive, err := zip.NewReader(f, size)
+	if err != nil || len(archive.File) == 0 {
+		return nil, true
+	}
+
+	largest := archive.File[0]
+	for _, entry := range archive.File[1:] {
+		if entry.UncompressedSize64 > largest.UncompressedSize64 {
+			largest = entry
+		}
+	}
+	return decode(largest.Open)
+}
+
+// flattenText turns decoded content into lowercase searchable text. NUL bytes are dropped so ASCII
+// strings written as UTF-16LE (common in .NET serialisation) read as plain text, and other binary
+// noise is replaced with spaces.
+func flattenText(content []byte) string {
+	if len(content) == 0 {
+		return ""
+	}
+
+	text := make([]byte, 0, len(content))
+	for _, b := range content {
+		switch {
+		case b == 0:
+			continue // UTF-16LE high bytes and padding
+		case b >= 0x20 && b < 0x7F:
+			text = append(text, b)
+		default:
+			text = append(text, ' ')
+		}
+	}
+	return strings.ToLower(string(text))
+}
+
+// Check describes the problems found when comparing a save file with what its filename claims.
+type Check struct {
+	Errors   []string // Problems that mean the file is not a usable save.
+	Warnings []string // Inconsistencies worth reporting that may still be a valid save.
+}
+
+// Validate checks the inspected file against a minimum size and the turn and player its filename claims.
+// claimedTurn may be 0 and claimedPlayer may be empty if the filename does not say.
+func (i *Info) Validate(minSize int64, claimedTurn int, claimedPlayer string) Check {
+	var check Check
+
+	if i.Size < minSize {
+		check.Errors = append(check.Errors, fmt.Sprintf("file is only %d bytes, smaller than the minimum save size of %d bytes", i.Size, minSize))
+	}
+	if i.Truncated {
+		check.Errors = append(check.Errors, fmt.Sprintf("%s data is truncated or corrupt", i.Format))
+	}
+	if i.Format == FormatUnknown {
+		check.Warnings = append(check.Warnings, "file format was not recognised as a Shadow Empire save")
+	}
+	if i.Turn != 0 && claimedTurn != 0 && i.Turn != claimedTurn {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("filename says turn %d but the save contains turn %d", claimedTurn, i.Turn))
+	}
+	if claimedPlayer != "" && i.Readable() && !i.ContainsName(claimedPlayer) {
+		check.Warnings = append(check.Warnings, fmt.Sprintf("player %s does not appear in the save", claimedPlayer))
+	}
+
+	return check
+}
@@ -0,0 +1,160 @@
+package savefile
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// content is the decoded text the test saves carry.
+var content = []byte("Shadow Empire save: player Alice, turn 7")
+
+func TestInspect(t *testing.T) {
+	gzipped := compress(t, func(b *bytes.Buffer) io.WriteCloser { return gzip.NewWriter(b) })
+	zipped := zipArchive(t)
+
+	tests := []struct {
+		name          string
+		data          []byte
+		wantFormat    Format
+		wantTruncated bool
+		wantTurn      int
+		wantName      string // A player name that should be found in the content, if any.
+	}{
+		{"gzip", gzipped, FormatGzip, false, 7, "alice"},
+		{"gzip missing its trailer", gzipped[:len(gzipped)-8], FormatGzip, true, 7, "alice"},
+		{"zlib", compress(t, func(b *bytes.Buffer) io.WriteCloser { return zlib.NewWriter(b) }), FormatZlib, false, 7, "alice"},
+		{"zip", zipped, FormatZip, false, 7, "alice"},
+		{"zip without its central directory", zipped[:len(zipped)/2], FormatZip, true, 0, ""},
+		{".NET stream with UTF-16 text", append(append([]byte{}, dotNetHeader...), utf16("Player Bob Turn 12")...), FormatDotNet, false, 12, "bob"},
+		{"plain text", content, FormatUnknown, false, 7, "ALICE"},
+		{"turn marker with punctuation", []byte("turn: 42"), FormatUnknown, false, 42, ""},
+		{"empty", nil, FormatUnknown, false, 0, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fsys := fstest.MapFS{"save.se": {Data: test.data}}
+			f, err := fsys.Open("save.se")
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer f.Close()
+
+			info, err := Inspect(f)
+			if err != nil {
+				t.Fatalf("Inspect() error = %v", err)
+			}
+			if info.Size != int64(len(test.data)) {
+				t.Errorf("Size = %d, want %d", info.Size, len(test.data))
+			}
+			if info.Format != test.wantFormat {
+				t.Errorf("Format = %s, want %s", info.Format, test.wantFormat)
+			}
+			if info.Truncated != test.wantTruncated {
+				t.Errorf("Truncated = %t, want %t", info.Truncated, test.wantTruncated)
+			}
+			if info.Turn != test.wantTurn {
+				t.Errorf("Turn = %d, want %d", info.Turn, test.wantTurn)
+			}
+			if test.wantName != "" && !info.ContainsName(test.wantName) {
+				t.Errorf("ContainsName(%q) = false, want true", test.wantName)
+			}
+			if info.ContainsName("carol") {
+				t.Error(`ContainsName("carol") = true, want false`)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	readable := Info{Size: 2048, Format: FormatGzip, Turn: 7, text: "player alice, turn 7"}
+
+	tests := []struct {
+		name          string
+		info          Info
+		claimedTurn   int
+		claimedPlayer string
+		wantErrors    []string
+		wantWarnings  []string
+	}{
+		{"consistent", readable, 7, "alice", nil, nil},
+		{"nothing claimed", readable, 0, "", nil, nil},
+		{"too small", Info{Size: 10, Format: FormatGzip}, 0, "", []string{"only 10 bytes"}, nil},
+		{"truncated", Info{Size: 2048, Format: FormatZip, Truncated: true}, 0, "", []string{"zip data is truncated"}, nil},
+		{"unknown format", Info{Size: 2048, Format: FormatUnknown}, 0, "", nil, []string{"not recognised"}},
+		{"wrong turn", readable, 8, "", nil, []string{"filename says turn 8 but the save contains turn 7"}},
+		{"missing player", readable, 7, "bob", nil, []string{"player bob does not appear"}},
+		{"unreadable content isn't checked for the player", Info{Size: 2048, Format: FormatDotNet}, 7, "bob", nil, nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			check := test.info.Validate(1024, test.claimedTurn, test.claimedPlayer)
+			assertMessages(t, "Errors", check.Errors, test.wantErrors)
+			assertMessages(t, "Warnings", check.Warnings, test.wantWarnings)
+		})
+	}
+}
+
+// assertMessages checks that each message contains the corresponding wanted text.
+func assertMessages(t *testing.T, field string, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s = %q, want %d messages", field, got, len(want))
+		return
+	}
+	for i := range want {
+		if !strings.Contains(got[i], want[i]) {
+			t.Errorf("%s[%d] = %q, want one containing %q", field, i, got[i], want[i])
+		}
+	}
+}
+
+// compress returns content compressed with the writer newWriter returns.
+func compress(t *testing.T, newWriter func(*bytes.Buffer) io.WriteCloser) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := newWriter(&b)
+	if _, err := w.Write(content); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return b.Bytes()
+}
+
+// zipArchive returns a zip archive with content as its largest entry.
+func zipArchive(t *testing.T) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	w := zip.NewWriter(&b)
+	for name, data := range map[string][]byte{"meta.txt": []byte("v1"), "save.dat": content} {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		if _, err := entry.Write(data); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return b.Bytes()
+}
+
+// utf16 encodes ASCII text as UTF-16LE, as .NET writes strings.
+func utf16(text string) []byte {
+	var encoded []byte
+	for _, c := range []byte(text) {
+		encoded = append(encoded, c, 0)
+	}
+	return encoded
+}
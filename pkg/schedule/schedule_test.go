@@ -0,0 +1,123 @@
+package schedule
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata" // The DST tests need a time zone database, which may not be installed
+)
+
+// monday is the time the Next tests start from: Monday 6 January 2025, 12:00 UTC.
+var monday = time.Date(2025, time.January, 6, 12, 0, 0, 0, time.UTC)
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", monday, monday.Add(time.Minute)},
+		{"range", "0 9-17 * * *", monday, date(2025, 1, 6, 13, 0)},
+		{"range wraps to the next day", "0 9-11 * * *", monday, date(2025, 1, 7, 9, 0)},
+		{"list", "0 8,20 * * *", monday, date(2025, 1, 6, 20, 0)},
+		{"step", "*/15 * * * *", monday, date(2025, 1, 6, 12, 15)},
+		{"step from a start", "5/20 * * * *", monday, date(2025, 1, 6, 12, 5)},
+		{"step within a range", "0 9-17/4 * * *", monday, date(2025, 1, 6, 13, 0)},
+		{"weekday name", "0 18 * * SUN", monday, date(2025, 1, 12, 18, 0)},
+		{"month name", "0 0 1 feb *", monday, date(2025, 2, 1, 0, 0)},
+		{"sunday as 0", "0 18 * * 0", monday, date(2025, 1, 12, 18, 0)},
+		{"sunday as 7", "0 18 * * 7", monday, date(2025, 1, 12, 18, 0)},
+		{"weekday range ending on 7", "0 9 * * 6-7", monday, date(2025, 1, 11, 9, 0)},
+		{"day of month or day of week", "0 0 15 * MON", monday, date(2025, 1, 13, 0, 0)},
+		{"day of month or day of week, day of month first", "0 0 15 * MON", date(2025, 1, 13, 1, 0), date(2025, 1, 15, 0, 0)},
+		{"stepped day of month and day of week", "0 0 */2 * MON", monday, date(2025, 1, 13, 0, 0)},
+		{"day of month and stepped day of week", "0 0 10 * */2", monday, date(2025, 4, 10, 0, 0)},
+		{"strictly after", "0 12 * * *", monday, date(2025, 1, 7, 12, 0)},
+		{"seconds are ignored", "1 12 * * *", monday.Add(30 * time.Second), date(2025, 1, 6, 12, 1)},
+		{"leap day", "0 0 29 2 *", monday, date(2028, 2, 29, 0, 0)},
+		{"shorthand", "@weekly", monday, date(2025, 1, 12, 0, 0)},
+		{"shorthand is case-insensitive", "@Monthly", monday, date(2025, 2, 1, 0, 0)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := Parse(test.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", test.spec, err)
+			}
+			if got := s.Next(test.from); !got.Equal(test.want) {
+				t.Errorf("Next(%s) = %s, want %s", test.from, got, test.want)
+			}
+		})
+	}
+}
+
+func TestNextDaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+	// Clocks went forward from 02:00 to 03:00 on 9 March 2025 and back from 02:00 to 01:00 on 2 November
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"keeps the local time across the change", "0 18 * * *",
+			time.Date(2025, 3, 8, 18, 0, 0, 0, newYork), time.Date(2025, 3, 9, 18, 0, 0, 0, newYork)},
+		{"skips a time that doesn't exist", "30 2 * * *",
+			time.Date(2025, 3, 8, 12, 0, 0, 0, newYork), time.Date(2025, 3, 10, 2, 30, 0, 0, newYork)},
+		{"hourly across the gap", "0 * * * *",
+			time.Date(2025, 3, 9, 1, 30, 0, 0, newYork), time.Date(2025, 3, 9, 3, 0, 0, 0, newYork)},
+		{"keeps the local time when clocks go back", "0 18 * * *",
+			time.Date(2025, 11, 1, 18, 0, 0, 0, newYork), time.Date(2025, 11, 2, 18, 0, 0, 0, newYork)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s, err := Parse(test.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", test.spec, err)
+			}
+			if got := s.Next(test.from); !got.Equal(test.want) {
+				t.Errorf("Next(%s) = %s, want %s", test.from, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		spec string
+		want string
+	}{
+		{"too few fields", "0 18 * *", "must have 5 fields"},
+		{"too many fields", "0 0 18 * * *", "must have 5 fields"},
+		{"unknown shorthand", "@yearly", "unknown schedule"},
+		{"minute out of range", "60 * * * *", "invalid minute"},
+		{"day of week out of range", "0 0 * * 8", "invalid day of week"},
+		{"unknown name", "0 0 * * SUNDAY", "invalid day of week"},
+		{"name in the wrong field", "0 0 * MON *", "invalid month"},
+		{"backwards range", "0 17-9 * * *", "invalid range"},
+		{"zero step", "*/0 * * * *", "invalid step"},
+		{"day that never comes", "0 0 30 2 *", "never fires"},
+		{"days that never come", "0 0 31 4,6,9,11 *", "never fires"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.spec)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", test.spec, err, test.want)
+			}
+		})
+	}
+}
+
+// date returns a time in UTC.
+func date(year int, month time.Month, day, hour, minute int) time.Time {
+	return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+}
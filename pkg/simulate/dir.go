@@ -0,0 +1,131 @@
+package simulate
+
+import (
+	"bytes"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Clock is a clock that only moves when it is advanced.
+type Clock struct {
+	now time.Time
+}
+
+// Now returns the simulated time.
+func (c *Clock) Now() time.Time {
+	return c.now
+}
+
+// Advance moves the clock forward by d.
+func (c *Clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// Dir is an in-memory watch directory. The directory part of a path is ignored, so every file lives
+// in the one directory, and files are timestamped with the simulated clock when they are written.
+type Dir struct {
+	clock *Clock
+	files map[string]*file
+}
+
+// file is a file in a Dir.
+type file struct {
+	name    string
+	data    []byte
+	modTime time.Time
+}
+
+// NewDir creates an empty directory whose files are timestamped by clock.
+func NewDir(clock *Clock) *Dir {
+	return &Dir{clock: clock, files: make(map[string]*file)}
+}
+
+// Write creates or overwrites a file.
+func (d *Dir) Write(name string, data []byte) {
+	name = filepath.Base(name)
+	d.files[name] = &file{name: name, data: data, modTime: d.clock.Now()}
+}
+
+// Remove deletes a file.
+func (d *Dir) Remove(name string) error {
+	name = filepath.Base(name)
+	if _, ok := d.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(d.files, name)
+	return nil
+}
+
+// ReadDir lists the files, sorted by name like os.ReadDir.
+func (d *Dir) ReadDir(string) ([]fs.DirEntry, error) {
+	var entries []fs.DirEntry
+	for _, f := range d.files {
+		entries = append(entries, fs.FileInfoToDirEntry(f.info()))
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int { return strings.Compare(a.Name(), b.Name()) })
+	return entries, nil
+}
+
+// Stat describes a file.
+func (d *Dir) Stat(name string) (fs.FileInfo, error) {
+	f, ok := d.files[filepath.Base(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return f.info(), nil
+}
+
+// Open opens a file for reading.
+func (d *Dir) Open(name string) (fs.File, error) {
+	f, ok := d.files[filepath.Base(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return &openFile{Reader: bytes.NewReader(f.data), file: f}, nil
+}
+
+// Rename renames a file, keeping its modification time.
+func (d *Dir) Rename(oldPath, newPath string) error {
+	oldName, newName := filepath.Base(oldPath), filepath.Base(newPath)
+	f, ok := d.files[oldName]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldPath, Err: fs.ErrNotExist}
+	}
+	delete(d.files, oldName)
+	d.files[newName] = &file{name: newName, data: f.data, modTime: f.modTime}
+	return nil
+}
+
+// WriteFile creates or overwrites a file.
+func (d *Dir) WriteFile(name string, data []byte) error {
+	d.Write(name, data)
+	return nil
+}
+
+func (f *file) info() fs.FileInfo {
+	return fileInfo{f}
+}
+
+// fileInfo describes a file in a Dir.
+type fileInfo struct {
+	f *file
+}
+
+func (i fileInfo) Name() string       { return i.f.name }
+func (i fileInfo) Size() int64        { return int64(len(i.f.data)) }
+func (i fileInfo) Mode() fs.FileMode  { return 0o644 }
+func (i fileInfo) ModTime() time.Time { return i.f.modTime }
+func (i fileInfo) IsDir() bool        { return false }
+func (i fileInfo) Sys() any           { return nil }
+
+// openFile is a file in a Dir opened for reading.
+type openFile struct {
+	*bytes.Reader
+	file *file
+}
+
+func (o *openFile) Stat() (fs.FileInfo, error) { return o.file.info(), nil }
+func (o *openFile) Close() error               { return nil }
@@ -0,0 +1,419 @@
+// Package simulate replays a scripted sequence of file events against the monitor and checks the
+// notifications that result. The monitor runs as usual, but against an in-memory watch directory,
+// with a clock that only moves when the script says so, and with notifications captured instead of
+// sent. It backs the simulate command, for reproducing a reported problem, and can be driven from
+// Go tests in the same way.
+//
+// A script has one directive per line. Anything after a # is a comment, and arguments containing
+// spaces can be double-quoted.
+//
+//	set <NAME> <value>           Set a configuration variable, such as USER_MAPPINGS or TURN_DEADLINE
+//	clock <time>                 Set the start time (RFC 3339), 2025-01-06T12:00:00Z by default
+//	existing <file> [bytes]      Add a file that is already in the directory when the monitor starts
+//	write <file> [bytes]         Create or overwrite a file
+//	delete <file>                Delete a file
+//	rename <old> <new>           Rename a file
+//	wait <duration>              Advance the clock, polling the directory every 5s as the monitor does
+//	skip                         Skip the current player, as an admin would
+//	pause [duration]             Pause the game, until the duration has passed if one is given
+//	resume                       Resume the game
+//	expect <player> [text]       A notification was sent to the player ("channel" for the game channel)
+//	                             containing the text (case-insensitive)
+//	expect-none                  Every notification so far has been matched by an expect
+//	expect-turn <turn> <player>  It is the player's turn
+//
+// set, clock and existing must come before anything else; the monitor starts at the first other
+// directive. The configuration is read from the environment as usual, with the script's set
+// directives taking precedence; the environment itself is not changed. Saves are inspected and
+// archived as usual, with the archive kept in a temporary directory. Saves are picked up by the
+// polls made while waiting, so allow for FILE_DEBOUNCE_MS after writing one.
+package simulate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/events"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/jsonl"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// DefaultStart is the simulated time a script starts at unless it sets the clock.
+var DefaultStart = time.Date(2025, time.January, 6, 12, 0, 0, 0, time.UTC)
+
+// defaultFileSize is the size of files written without one.
+const defaultFileSize = 4096
+
+// Notification is a notification the monitor sent during a simulation.
+type Notification struct {
+	Time      time.Time
+	Backend   string // webhook or dm
+	Recipient string // Username of the player, or "game channel" for announcements.
+	Payload   *types.DiscordWebhook
+}
+
+// Text returns the message content followed by each embed field, one per line.
+func (n Notification) Text() string {
+	lines := []string{n.Payload.Content}
+	for _, embed := range n.Payload.Embeds {
+		for _, field := range embed.Fields {
+			lines = append(lines, field.Name, field.Value)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Result is the outcome of a simulation.
+type Result struct {
+	Notifications []Notification // Every notification sent, in order.
+	Expectations  int            // Number of expect directives checked.
+	Failures      []string       // Expectations that weren't met.
+}
+
+// Passed reports whether every expectation was met.
+func (r *Result) Passed() bool {
+	return len(r.Failures) == 0
+}
+
+// runner holds a simulation in progress.
+type runner struct {
+	clock   *Clock
+	dir     *Dir
+	state   *monitor.GameState
+	poller  *monitor.Poller
+	env     map[string]string // Variables set by the script.
+	tempDir string            // Holds the turn history, event log and archive.
+	matched []bool            // Whether each notification has been matched by an expect.
+	start   time.Time         // When the monitor started.
+	result  Result
+}
+
+// Run runs a simulation script. An error is returned if the script is invalid or the monitor couldn't
+// be started; expectations that aren't met are reported in the result.
+func Run(script io.Reader) (*Result, error) {
+	r := &runner{clock: &Clock{now: DefaultStart}, env: make(map[string]string)}
+	r.dir = NewDir(r.clock)
+	defer r.close()
+
+	scanner := bufio.NewScanner(script)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		args, err := splitArgs(text)
+		if err != nil {
+			return &r.result, fmt.Errorf("line %d: %w", line, err)
+		}
+		if err := r.do(line, args); err != nil {
+			return &r.result, fmt.Errorf("line %d: %s: %w", line, args[0], err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return &r.result, fmt.Errorf("error reading script: %w", err)
+	}
+	return &r.result, nil
+}
+
+// do runs one directive, starting the monitor first if it needs to be running.
+func (r *runner) do(line int, args []string) error {
+	directive, args := args[0], args[1:]
+
+	switch directive {
+	case "set", "clock", "existing":
+		if r.poller != nil {
+			return errors.New("must come before the monitor starts")
+		}
+	default:
+		if r.poller == nil {
+			if err := r.startMonitor(); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("🎬 [+%s] %s\n", r.clock.Now().Sub(r.start), strings.Join(append([]string{directive}, args...), " "))
+	}
+
+	switch directive {
+	case "set":
+		if len(args) != 2 {
+			return errors.New("usage: set <NAME> <value>")
+		}
+		r.env[args[0]] = args[1]
+	case "clock":
+		if len(args) != 1 {
+			return errors.New("usage: clock <time>")
+		}
+		start, err := time.Parse(time.RFC3339, args[0])
+		if err != nil {
+			return err
+		}
+		r.clock.now = start
+	case "existing", "write":
+		if len(args) < 1 || len(args) > 2 {
+			return fmt.Errorf("usage: %s <file> [bytes]", directive)
+		}
+		size := defaultFileSize
+		if len(args) == 2 {
+			var err error
+			if size, err = strconv.Atoi(args[1]); err != nil || size < 0 {
+				return fmt.Errorf("invalid size %s", args[1])
+			}
+		}
+		r.dir.Write(args[0], content(args[0], r.clock.Now(), size))
+	case "delete":
+		if len(args) != 1 {
+			return errors.New("usage: delete <file>")
+		}
+		return r.dir.Remove(args[0])
+	case "rename":
+		if len(args) != 2 {
+			return errors.New("usage: rename <old> <new>")
+		}
+		return r.dir.Rename(args[0], args[1])
+	case "wait":
+		if len(args) != 1 {
+			return errors.New("usage: wait <duration>")
+		}
+		d, err := time.ParseDuration(args[0])
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration %s", args[0])
+		}
+		r.wait(d)
+	case "skip":
+		if _, _, err := r.state.SkipCurrentPlayer(); err != nil {
+			return err
+		}
+	case "pause":
+		var until time.Time
+		if len(args) == 1 {
+			d, err := time.ParseDuration(args[0])
+			if err != nil || d <= 0 {
+				return fmt.Errorf("invalid duration %s", args[0])
+			}
+			until = r.clock.Now().Add(d)
+		}
+		r.state.Pause(until, "")
+	case "resume":
+		r.state.Resume()
+	case "expect":
+		if len(args) < 1 {
+			return errors.New("usage: expect <player> [text]")
+		}
+		r.expect(line, args[0], strings.Join(args[1:], " "))
+	case "expect-none":
+		r.expectNone(line)
+	case "expect-turn":
+		if len(args) != 2 {
+			return errors.New("usage: expect-turn <turn> <player>")
+		}
+		turn, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid turn %s", args[0])
+		}
+		r.expectTurn(line, turn, args[1])
+	default:
+		return errors.New("unknown directive")
+	}
+	return nil
+}
+
+// startMonitor starts the monitor against the simulated directory and clock, capturing notifications.
+func (r *runner) startMonitor() error {
+	cfg, err := config.Load(r.lookup)
+	if err != nil {
+		for _, problem := range strings.Split(err.Error(), "\n") {
+			fmt.Printf("⚠️ %s\n", problem)
+		}
+	}
+	if len(cfg.Players) == 0 {
+		return errors.New("no players are configured, set USER_MAPPINGS in the environment or the script")
+	}
+
+	if r.tempDir, err = os.MkdirTemp("", "simulation"); err != nil {
+		return err
+	}
+	cfg.WatchDirectory = "simulation"
+	if cfg.ArchiveDirectory != "" {
+		cfg.ArchiveDirectory = filepath.Join(r.tempDir, "archive")
+	}
+
+	r.state = monitor.NewGameState(cfg.GameName, cfg.Players)
+	r.state.SetClock(r.clock)
+	turnLog := jsonl.New[history.Turn](filepath.Join(r.tempDir, "history.jsonl"))
+	eventLog := jsonl.New[events.Event](filepath.Join(r.tempDir, "events.jsonl"))
+	if err := r.state.SetLogs(turnLog, eventLog); err != nil {
+		return err
+	}
+
+	webhook.Configure(cfg)
+	webhook.SetClock(r.clock)
+	webhook.SetNotifier(r)
+	if r.poller, err = monitor.NewPoller(cfg, r.state, r.dir); err != nil {
+		return err
+	}
+	r.start = r.clock.Now()
+	fmt.Printf("🎬 Simulation started at %s\n", r.start.Format(time.DateTime))
+	return nil
+}
+
+// lookup reads a configuration variable, from the script if it set one and otherwise from the environment.
+func (r *runner) lookup(name string) string {
+	if value, ok := r.env[name]; ok {
+		return value
+	}
+	return os.Getenv(name)
+}
+
+// close stops capturing notifications and removes the simulation's files.
+func (r *runner) close() {
+	if r.poller != nil {
+		webhook.SetNotifier(nil)
+		webhook.SetClock(nil)
+	}
+	if r.tempDir != "" {
+		os.RemoveAll(r.tempDir)
+	}
+}
+
+// Notify captures a notification instead of sending it.
+func (r *runner) Notify(backend, recipient string, payload *types.DiscordWebhook) error {
+	notification := Notification{Time: r.clock.Now(), Backend: backend, Recipient: recipient, Payload: payload}
+	r.result.Notifications = append(r.result.Notifications, notification)
+	r.matched = append(r.matched, false)
+	fmt.Printf("📨 [+%s] %s to %s: %s\n", r.clock.Now().Sub(r.start), backend, recipient, strings.SplitN(payload.Content, "\n", 2)[0])
+	return nil
+}
+
+// wait advances the clock by d, polling the directory at the monitor's poll interval.
+func (r *runner) wait(d time.Duration) {
+	for d > 0 {
+		step := min(d, monitor.PollInterval)
+		r.clock.Advance(step)
+		d -= step
+		r.poller.Poll()
+	}
+}
+
+// expect matches the earliest unmatched notification to recipient that contains text.
+func (r *runner) expect(line int, recipient, text string) {
+	r.result.Expectations++
+	for i, notification := range r.result.Notifications {
+		if r.matched[i] || !recipientMatches(notification.Recipient, recipient) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(notification.Text()), strings.ToLower(text)) {
+			r.matched[i] = true
+			fmt.Printf("✅ Line %d: notification to %s found\n", line, recipient)
+			return
+		}
+	}
+
+	description := fmt.Sprintf("a notification to %s", recipient)
+	if text != "" {
+		description += fmt.Sprintf(" containing %q", text)
+	}
+	r.fail(line, fmt.Sprintf("expected %s, unmatched notifications: %s", description, r.unmatched()))
+}
+
+// expectNone checks that every notification has been matched.
+func (r *runner) expectNone(line int) {
+	r.result.Expectations++
+	if unmatched := r.unmatched(); unmatched != "none" {
+		r.fail(line, fmt.Sprintf("expected no unmatched notifications, got: %s", unmatched))
+		return
+	}
+	fmt.Printf("✅ Line %d: no unmatched notifications\n", line)
+}
+
+// expectTurn checks whose turn it is.
+func (r *runner) expectTurn(line, turn int, player string) {
+	r.result.Expectations++
+	snapshot := r.state.Snapshot()
+	current, ok := snapshot.CurrentMapping()
+	if !ok || snapshot.CurrentTurn != turn || !strings.EqualFold(current.Username, player) {
+		actual := "no current player"
+		if ok {
+			actual = fmt.Sprintf("turn %d for %s", snapshot.CurrentTurn, current.Username)
+		}
+		r.fail(line, fmt.Sprintf("expected turn %d for %s, got %s", turn, player, actual))
+		return
+	}
+	fmt.Printf("✅ Line %d: it's turn %d for %s\n", line, turn, current.Username)
+}
+
+// fail records an expectation that wasn't met.
+func (r *runner) fail(line int, message string) {
+	failure := fmt.Sprintf("line %d: %s", line, message)
+	r.result.Failures = append(r.result.Failures, failure)
+	fmt.Printf("❌ %s\n", failure)
+}
+
+// unmatched lists the notifications not yet matched by an expect.
+func (r *runner) unmatched() string {
+	var list []string
+	for i, notification := range r.result.Notifications {
+		if !r.matched[i] {
+			list = append(list, fmt.Sprintf("%s (%q)", notification.Recipient, strings.SplitN(notification.Payload.Content, "\n", 2)[0]))
+		}
+	}
+	if len(list) == 0 {
+		return "none"
+	}
+	return strings.Join(list, ", ")
+}
+
+// recipientMatches reports whether a notification's recipient is the one named in a script.
+func recipientMatches(recipient, name string) bool {
+	if strings.EqualFold(name, "channel") {
+		name = "game channel"
+	}
+	return strings.EqualFold(recipient, name)
+}
+
+// content returns size bytes of content for a simulated file, different each time it is written.
+func content(name string, written time.Time, size int) []byte {
+	pattern := fmt.Sprintf("%s written at %s\n", name, written.Format(time.RFC3339Nano))
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = pattern[i%len(pattern)]
+	}
+	return data
+}
+
+// splitArgs splits a directive into its arguments, keeping double-quoted arguments together and
+// dropping any trailing comment.
+func splitArgs(text string) ([]string, error) {
+	var args []string
+	for text = strings.TrimSpace(text); text != ""; text = strings.TrimSpace(text) {
+		if text[0] == '#' {
+			break // The rest of the line is a comment
+		}
+		if text[0] == '"' {
+			end := strings.IndexByte(text[1:], '"')
+			if end == -1 {
+				return nil, errors.New("unterminated quote")
+			}
+			args = append(args, text[1:end+1])
+			text = text[end+2:]
+			continue
+		}
+		end := strings.IndexAny(text, " \t")
+		if end == -1 {
+			end = len(text)
+		}
+		args = append(args, text[:end])
+		text = text[end:]
+	}
+	return args, nil
+}
@@ -0,0 +1,190 @@
+package simulate
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// players is the turn order shared by the test scripts.
+const players = `set USER_MAPPINGS "1 alice 111,2 bob 222,3 carol 333"
+set GAME_NAME pbem1
+set FILE_DEBOUNCE_MS 1000
+set DISCORD_WEBHOOK_URL http://discord.invalid/webhook
+`
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{
+			name: "hand-over",
+			script: players + `
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob "pbem1_turn1_carol"
+expect-turn 1 bob
+expect-none
+`,
+		},
+		{
+			name: "wrap-around",
+			script: players + `
+write pbem1_turn1_bob.sav
+wait 1m
+write pbem1_turn1_carol.sav
+wait 1m
+write pbem1_turn2_alice.sav
+wait 1m
+expect bob
+expect carol "pbem1_turn2_alice"
+expect alice "pbem1_turn2_bob"
+expect-turn 2 alice
+expect-none
+`,
+		},
+		{
+			name: "resubmission",
+			script: players + `
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+write pbem1_turn1_bob.sav      # alice saved again over the same file
+wait 1m
+expect bob
+expect-turn 1 bob
+expect-none
+`,
+		},
+		{
+			name: "out-of-order save",
+			script: players + `
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+write pbem1_turn2_alice.sav    # carol's turn was missed
+wait 1m
+expect channel "out of order"
+expect-turn 1 bob
+expect-none
+`,
+		},
+		{
+			name: "deadline skip",
+			script: players + `set TURN_DEADLINE 48h
+set TURN_DEADLINE_WARNING 12h
+set FILE_AGE_LIMIT 1000h
+
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+wait 36h
+expect bob "final warning"
+wait 13h
+expect bob "skipped"
+expect carol "bob has been skipped"
+expect-turn 1 carol
+expect-none
+`,
+		},
+		{
+			name: "deadline skip limit",
+			script: players + `set TURN_DEADLINE 48h
+set TURN_DEADLINE_MAX_SKIPS 1
+set FILE_AGE_LIMIT 1000h
+
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+wait 49h
+expect bob "final warning"
+expect bob "skipped"
+expect carol
+write pbem1_turn2_alice.sav
+wait 1m
+expect alice
+write pbem1_turn2_bob.sav
+wait 1m
+expect bob
+wait 49h                       # bob has no skips left, so the turn stays put
+expect bob "final warning"
+expect channel "already been skipped 1 times"
+expect-turn 2 bob
+expect-none
+`,
+		},
+		{
+			name: "pause",
+			script: players + `set TURN_DEADLINE 24h
+set TURN_DEADLINE_WARNING 12h
+set FILE_AGE_LIMIT 1000h
+
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+pause
+expect channel "paused"
+wait 48h                       # no warnings or skips while paused
+expect-none
+resume
+expect channel "resumed"
+wait 11h                       # the paused time doesn't count towards the deadline
+expect-turn 1 bob
+expect-none
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := Run(strings.NewReader(test.script))
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			for _, failure := range result.Failures {
+				t.Error(failure)
+			}
+			if result.Expectations == 0 {
+				t.Error("no expectations were checked")
+			}
+		})
+	}
+}
+
+func TestRunSetDoesNotChangeEnvironment(t *testing.T) {
+	t.Setenv("TURN_DEADLINE", "")
+	script := players + `set TURN_DEADLINE 48h
+write pbem1_turn1_bob.sav
+wait 1m
+expect bob
+`
+	if _, err := Run(strings.NewReader(script)); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if value := os.Getenv("TURN_DEADLINE"); value != "" {
+		t.Errorf("TURN_DEADLINE = %q after the simulation, want it unchanged", value)
+	}
+}
+
+func TestRunInvalidScript(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   string
+	}{
+		{"unknown directive", players + "jump 5\n", "unknown directive"},
+		{"set after start", players + "wait 1m\nset TURN_DEADLINE 48h\n", "must come before the monitor starts"},
+		{"bad duration", players + "wait soon\n", "invalid duration"},
+		{"unterminated quote", players + "write \"pbem1_turn1_bob.sav\n", "unterminated quote"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Run(strings.NewReader(test.script))
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Run() error = %v, want one containing %q", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package state persists the bot's turn-tracking state to a JSON file, so it survives restarts
+// and can be changed by CLI commands while the bot is running.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Writers take a lock file next to the state file while they check the revision and save.
+const (
+	lockTimeout = 5 * time.Second  // How long to wait for another writer to finish.
+	lockStale   = 30 * time.Second // Age after which a lock file is assumed to be left over from a crash.
+)
+
+// ErrStale is returned by Save when another process has saved a newer revision since this store last
+// read or wrote the file. The newer state should be loaded rather than overwritten.
+var ErrStale = errors.New("the state file was changed by another process")
+
+// Data is the persisted state of a game.
+type Data struct {
+	Revision         int64          `json:"revision"`                     // Incremented on every save, so writers can spot each other's changes.
+	CurrentTurn      int            `json:"current_turn"`                 // Turn the current player is playing.
+	CurrentPlayer    string         `json:"current_player"`               // Username of the current player, empty if unknown.
+	LastSave         string         `json:"last_save"`                    // Save file that handed over the current turn.
+	TurnStarted      time.Time      `json:"turn_started"`                 // When the current player was notified.
+	SittingOut       []string       `json:"sitting_out,omitempty"`        // Usernames of players passed over until reinstated.
+	Skips            map[string]int `json:"skips,omitempty"`              // Times each player (by lowercase username) has been skipped.
+	Paused           bool           `json:"paused,omitempty"`             // True while the game is paused.
+	PausedAt         time.Time      `json:"paused_at,omitzero"`           // When the game was paused.
+	PausedUntil      time.Time      `json:"paused_until,omitzero"`        // When the game resumes by itself, zero if not set.
+	PauseReason      string         `json:"pause_reason,omitempty"`       // Why the game is paused.
+	TurnPaused       time.Duration  `json:"turn_paused,omitempty"`        // Time the current turn spent paused before the current pause.
+	TurnReminders    int            `json:"turn_reminders,omitempty"`     // Reminders sent to the current player this turn.
+	DeadlineWarned   bool           `json:"deadline_warned,omitempty"`    // Whether the current player has had the final deadline warning.
+	DeadlineMissed   bool           `json:"deadline_missed,omitempty"`    // Whether the current player's deadline has passed and been dealt with.
+	LastSummary      time.Time      `json:"last_summary,omitzero"`        // When the last turn time summary was posted.
+	LastDigest       time.Time      `json:"last_digest,omitzero"`         // When the last scheduled digest was posted.
+	LastRoundSummary int            `json:"last_round_summary,omitempty"` // Last round a summary was posted for.
+	PendingRename    *Rename        `json:"pending_rename,omitempty"`     // Outstanding request to rename a misnamed save.
+}
+
+// Rename is a request for a player to rename a misnamed save.
+type Rename struct {
+	Original  string    `json:"original"`  // The misnamed file.
+	Expected  string    `json:"expected"`  // The exact name the save should have.
+	Saver     string    `json:"saver"`     // Username of the player asked to rename it.
+	Requested time.Time `json:"requested"` // When the player was asked.
+}
+
+// Store reads and writes the state file.
+type Store struct {
+	path string
+
+	mu           sync.Mutex
+	lastRevision int64 // Revision last read or written by this store.
+}
+
+// NewStore creates a store for the state file at path.
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Path returns the location of the state file.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Load reads the state file. It returns nil data and no error if the file does not exist yet.
+func (s *Store) Load() (*Data, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadLocked()
+}
+
+func (s *Store) loadLocked() (*Data, error) {
+	data, err := s.read()
+	if data != nil {
+		s.lastRevision = data.Revision
+	}
+	return data, err
+}
+
+// read reads and parses the state file, returning nil data if it does not exist.
+func (s *Store) read() (*Data, error) {
+	content, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading state file: %w", err)
+	}
+
+	var data Data
+	if err := json.Unmarshal(content, &data); err != nil {
+		return nil, fmt.Errorf("error parsing state file %s: %w", s.path, err)
+	}
+	return &data, nil
+}
+
+// LoadIfChanged reads the state file only if another process has saved a new revision since this
+// store last read or wrote it. It returns nil data if nothing changed. The revision is compared
+// rather than the modification time, so a write in the same second as the last one isn't missed.
+func (s *Store) LoadIfChanged() (*Data, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.read()
+	if err != nil || data == nil || data.Revision == s.lastRevision {
+		return nil, err
+	}
+	s.lastRevision = data.Revision
+	return data, nil
+}
+
+// Save atomically writes data as the next revision of the state file. It returns ErrStale, and
+// writes nothing, if the file holds a revision this store hasn't seen.
+func (s *Store) Save(data *Data) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dir := filepath.Dir(s.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("error creating state directory: %w", err)
+		}
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	onDisk, err := s.read()
+	if err != nil {
+		return err
+	}
+	if onDisk != nil && onDisk.Revision != s.lastRevision {
+		return fmt.Errorf("%w (revision %d on disk, expected %d)", ErrStale, onDisk.Revision, s.lastRevision)
+	}
+
+	data.Revision = s.lastRevision + 1
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling state: %w", err)
+	}
+
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, content, 0o644); err != nil {
+		return fmt.Errorf("error writing state file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("error writing state file: %w", err)
+	}
+
+	s.lastRevision = data.Revision
+	return nil
+}
+
+// lock takes the lock file that serialises writers across processes, such as the running bot and the
+// rollback command, and returns a function that releases it.
+func (s *Store) lock() (func(), error) {
+	lockPath := s.path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("error locking state file: %w", err)
+		}
+
+		// A writer that crashed while holding the lock would otherwise block everyone forever
+		if stat, err := os.Stat(lockPath); err == nil && time.Since(stat.ModTime()) > lockStale {
+			fmt.Printf("⚠️ Removing stale state lock %s\n", lockPath)
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the state file lock %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
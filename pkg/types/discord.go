@@ -1,5 +1,7 @@
 package types
 
+import "encoding/json"
+
 // DiscordWebhook represents the complete structure for a Discord webhook request
 type DiscordWebhook struct {
 	Username  string  `json:"username"`
@@ -32,3 +34,104 @@ type Field struct {
 type Footer struct {
 	Text string `json:"text"`
 }
+
+// Interaction types sent by Discord to an interactions endpoint
+const (
+	InteractionTypePing               = 1
+	InteractionTypeApplicationCommand = 2
+)
+
+// Permission bits, as used in Member.Permissions and ApplicationCommand.DefaultMemberPermissions
+const (
+	PermissionAdministrator = 1 << 3
+	PermissionManageGuild   = 1 << 5
+)
+
+// Interaction response types returned to Discord
+const (
+	InteractionResponsePong                   = 1
+	InteractionResponseChannelMessage         = 4
+	InteractionResponseDeferredChannelMessage = 5
+)
+
+// Application command option types
+const (
+	CommandOptionString  = 3
+	CommandOptionInteger = 4
+)
+
+// ApplicationCommand describes a slash command registered with Discord
+type ApplicationCommand struct {
+	Name                     string                     `json:"name"`
+	Description              string                     `json:"description"`
+	Options                  []ApplicationCommandOption `json:"options,omitempty"`
+	DefaultMemberPermissions *string                    `json:"default_member_permissions,omitempty"` // Permission bit set needed to use the command, nil for everyone
+}
+
+// ApplicationCommandOption describes a single argument of a slash command
+type ApplicationCommandOption struct {
+	Type        int    `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// Interaction represents an incoming interaction (such as a slash command invocation)
+type Interaction struct {
+	ID            string           `json:"id"`
+	ApplicationID string           `json:"application_id"`
+	Type          int              `json:"type"`
+	Token         string           `json:"token"`
+	Data          *InteractionData `json:"data,omitempty"`
+	Member        *Member          `json:"member,omitempty"`
+	User          *User            `json:"user,omitempty"`
+}
+
+// InteractionData holds the command name and options of an application command interaction
+type InteractionData struct {
+	Name    string                  `json:"name"`
+	Options []InteractionDataOption `json:"options,omitempty"`
+}
+
+// InteractionDataOption is a single option value supplied with a slash command
+type InteractionDataOption struct {
+	Name  string          `json:"name"`
+	Type  int             `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Member represents the guild member who triggered an interaction
+type Member struct {
+	User        *User    `json:"user"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions string   `json:"permissions,omitempty"` // The member's permissions in the channel, as a decimal bit set
+}
+
+// User represents a Discord user
+type User struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// InteractionResponse is the reply sent back to Discord for an interaction
+type InteractionResponse struct {
+	Type int                      `json:"type"`
+	Data *InteractionResponseData `json:"data,omitempty"`
+}
+
+// InteractionResponseData is the message content of an interaction response
+type InteractionResponseData struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+}
+
+// Message is the body used to post a message to a channel with the bot token
+type Message struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+}
+
+// Channel represents a Discord channel, such as a direct message channel
+type Channel struct {
+	ID string `json:"id"`
+}
@@ -8,32 +8,62 @@ import (
 	"strings"
 )
 
-// UserMapping holds the order, username, and Discord ID for a user.
+// NotifyPreference controls where a user's turn notices and reminders are delivered.
+type NotifyPreference string
+
+const (
+	NotifyChannel NotifyPreference = "channel" // Post in the game channel via the webhook (default).
+	NotifyDM      NotifyPreference = "dm"      // Send a direct message instead of posting in the channel.
+	NotifyBoth    NotifyPreference = "both"    // Post in the channel and send a direct message.
+)
+
+// UserMapping holds the order, username, Discord ID and notification preference for a user.
 type UserMapping struct {
 	Order     int
 	Username  string
 	DiscordID string
+	Notify    NotifyPreference
 }
 
 // ParseUsers parses username to Discord ID mappings from a comma-separated environment variable
-// Format: "1 Username1 DiscordId1,2 Username2 DiscordId2"
+// Format: "1 Username1 DiscordId1,2 Username2 DiscordId2 dm"
+// The optional fourth field is the notification preference: channel (default), dm or both.
 // Returns a slice of UserMapping sorted by the order number.
 func ParseUsers(envVarName string) ([]UserMapping, error) {
-	var userMappings []UserMapping
 	envVar := os.Getenv(envVarName)
-
 	if envVar == "" {
 		return nil, fmt.Errorf("environment variable %s not found", envVarName)
 	}
 
-	pairs := strings.Split(envVar, ",")
+	userMappings, err := Parse(envVar)
+	if err != nil {
+		return nil, err
+	}
+	if len(userMappings) == 0 {
+		return nil, fmt.Errorf("no valid user mappings found in environment variable %s", envVarName)
+	}
+	return userMappings, nil
+}
+
+// Parse parses user mappings in the USER_MAPPINGS format, sorted by the order number.
+func Parse(value string) ([]UserMapping, error) {
+	var userMappings []UserMapping
+	pairs := strings.Split(value, ",")
 	for i, pair := range pairs {
-		parts := strings.SplitN(strings.TrimSpace(pair), " ", 3) // Split into 3 parts: order, username, discordId
-		if len(parts) == 3 {
+		parts := strings.Fields(pair) // Split into order, username, discordId and optional preference
+		if len(parts) == 3 || len(parts) == 4 {
 			orderStr := parts[0]
 			username := parts[1]
 			discordId := parts[2]
 
+			notify := NotifyChannel
+			if len(parts) == 4 {
+				notify = NotifyPreference(strings.ToLower(parts[3]))
+				if notify != NotifyChannel && notify != NotifyDM && notify != NotifyBoth {
+					return nil, fmt.Errorf("invalid notification preference '%s' in mapping part %d: expected channel, dm or both", parts[3], i+1)
+				}
+			}
+
 			order, err := strconv.Atoi(orderStr)
 			if err != nil {
 				return nil, fmt.Errorf("invalid order number '%s' in mapping part %d: %w", orderStr, i+1, err)
@@ -44,12 +74,13 @@ func ParseUsers(envVarName string) ([]UserMapping, error) {
 					Order:     order,
 					Username:  username,
 					DiscordID: discordId,
+					Notify:    notify,
 				})
 			} else {
 				return nil, fmt.Errorf("invalid format in mapping part %d: username or discordId is empty", i+1)
 			}
 		} else {
-			return nil, fmt.Errorf("invalid format in mapping part %d: expected 'order username discordId', got '%s'", i+1, strings.TrimSpace(pair))
+			return nil, fmt.Errorf("invalid format in mapping part %d: expected 'order username discordId [channel|dm|both]', got '%s'", i+1, strings.TrimSpace(pair))
 		}
 	}
 
@@ -74,9 +105,5 @@ func ParseUsers(envVarName string) ([]UserMapping, error) {
 		}
 	}
 
-	if len(userMappings) == 0 {
-		return nil, fmt.Errorf("no valid user mappings found in environment variable %s", envVarName)
-	}
-
 	return userMappings, nil
 }
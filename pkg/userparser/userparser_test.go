@@ -0,0 +1,82 @@
+package userparser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  []UserMapping
+	}{
+		{
+			name:  "channel by default",
+			value: "1 alice 111,2 bob 222",
+			want: []UserMapping{
+				{Order: 1, Username: "alice", DiscordID: "111", Notify: NotifyChannel},
+				{Order: 2, Username: "bob", DiscordID: "222", Notify: NotifyChannel},
+			},
+		},
+		{
+			name:  "preferences",
+			value: "1 alice 111 dm,2 bob 222 both,3 carol 333 channel",
+			want: []UserMapping{
+				{Order: 1, Username: "alice", DiscordID: "111", Notify: NotifyDM},
+				{Order: 2, Username: "bob", DiscordID: "222", Notify: NotifyBoth},
+				{Order: 3, Username: "carol", DiscordID: "333", Notify: NotifyChannel},
+			},
+		},
+		{
+			name:  "preferences are case-insensitive",
+			value: "1 alice 111 DM",
+			want:  []UserMapping{{Order: 1, Username: "alice", DiscordID: "111", Notify: NotifyDM}},
+		},
+		{
+			name:  "sorted by order, with extra spaces",
+			value: " 2  bob 222 , 1 alice 111 dm ",
+			want: []UserMapping{
+				{Order: 1, Username: "alice", DiscordID: "111", Notify: NotifyDM},
+				{Order: 2, Username: "bob", DiscordID: "222", Notify: NotifyChannel},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Parse(test.value)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", test.value, err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Parse(%q) = %+v, want %+v", test.value, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{"unknown preference", "1 alice 111 email", "invalid notification preference 'email' in mapping part 1"},
+		{"too few fields", "1 alice 111,2 bob", "invalid format in mapping part 2"},
+		{"too many fields", "1 alice 111 dm extra", "invalid format in mapping part 1"},
+		{"order isn't a number", "first alice 111", "invalid order number 'first'"},
+		{"duplicate order", "1 alice 111,1 bob 222", "duplicate order number 1"},
+		{"trailing comma", "1 alice 111,", "invalid format in mapping part 2"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Parse(test.value)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Errorf("Parse(%q) error = %v, want one containing %q", test.value, err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// maxDeliveries is the number of recent deliveries kept for the dashboard.
+const maxDeliveries = 50
+
+// Delivery records the outcome of sending one notification.
+type Delivery struct {
+	Time      time.Time
+	Backend   string // webhook or dm
+	Recipient string // Username of the player, or "game channel" for announcements.
+	Summary   string // First line of the message.
+	Status    string // HTTP status code, or "error" if no response was received.
+	Error     string // Why delivery failed, empty on success.
+}
+
+// deliveries holds the most recent deliveries, oldest first
+var (
+	deliveriesMu sync.Mutex
+	deliveries   []Delivery
+)
+
+// ErrShuttingDown is returned for notifications sent after Wait has been called.
+var ErrShuttingDown = errors.New("the bot is shutting down")
+
+// inFlight counts the notifications being sent, including any retries. Once closing is set by Wait, no
+// more are started, so the count can only go down while Wait is waiting for it.
+var inFlight struct {
+	mu      sync.Mutex
+	closing bool
+	sending sync.WaitGroup
+}
+
+// beginSend counts a notification as being sent, or returns ErrShuttingDown if Wait has been called.
+// Every successful call must be followed by a call to endSend.
+func beginSend() error {
+	inFlight.mu.Lock()
+	defer inFlight.mu.Unlock()
+	if inFlight.closing {
+		return ErrShuttingDown
+	}
+	inFlight.sending.Add(1)
+	return nil
+}
+
+// endSend marks a notification counted by beginSend as delivered or failed.
+func endSend() {
+	inFlight.sending.Done()
+}
+
+// Wait stops any more notifications from being sent, then blocks until every notification being sent
+// has been delivered or has failed, or until ctx is done. It is used when shutting down, so a
+// notification isn't cut off part way through its retries.
+func Wait(ctx context.Context) error {
+	inFlight.mu.Lock()
+	inFlight.closing = true
+	inFlight.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		inFlight.sending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// recordDelivery counts a delivery in the metrics and adds it to the delivery log.
+func recordDelivery(backend, recipient string, payload *types.DiscordWebhook, status string, err error) {
+	delivery := Delivery{
+		Time:      now(),
+		Backend:   backend,
+		Recipient: recipient,
+		Summary:   strings.SplitN(payload.Content, "\n", 2)[0],
+		Status:    status,
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		metrics.NotificationsFailed.Inc(backend, status)
+	} else {
+		metrics.NotificationsSent.Inc(backend, status)
+	}
+
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+	deliveries = append(deliveries, delivery)
+	if len(deliveries) > maxDeliveries {
+		deliveries = deliveries[len(deliveries)-maxDeliveries:]
+	}
+}
+
+// RecentDeliveries returns the most recent notification deliveries, newest first.
+func RecentDeliveries() []Delivery {
+	deliveriesMu.Lock()
+	defer deliveriesMu.Unlock()
+
+	recent := make([]Delivery, len(deliveries))
+	for i, delivery := range deliveries {
+		recent[len(deliveries)-1-i] = delivery
+	}
+	return recent
+}
@@ -0,0 +1,97 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/discord"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// dmChannels caches the direct message channel ID for each Discord user ID
+var (
+	dmChannelsMu sync.Mutex
+	dmChannels   = make(map[string]string)
+)
+
+// sendDirectMessage delivers the payload's content and embeds as a direct message using the configured bot token
+func sendDirectMessage(payload *types.DiscordWebhook, target userparser.UserMapping) error {
+	if err := beginSend(); err != nil {
+		fmt.Printf("⚠️ Not sending direct message to %s: %v\n", target.Username, err)
+		return err
+	}
+	defer endSend()
+
+	if intercepted, err := intercept("dm", target.Username, payload); intercepted {
+		return err
+	}
+
+	err := postDirectMessage(payload, target)
+	status := "200"
+	if err != nil {
+		status = "error"
+		var statusErr *discord.StatusError
+		if errors.As(err, &statusErr) {
+			status = strconv.Itoa(statusErr.StatusCode)
+		}
+	}
+	recordDelivery("dm", target.Username, payload, status, err)
+	return err
+}
+
+// postDirectMessage opens (or reuses) the DM channel with the target and posts the message
+func postDirectMessage(payload *types.DiscordWebhook, target userparser.UserMapping) error {
+	settings.mu.RLock()
+	client := discord.NewClient(settings.apiBaseURL, settings.botToken)
+	settings.mu.RUnlock()
+	if client.Token == "" {
+		return fmt.Errorf("DISCORD_BOT_TOKEN is not set, cannot send direct messages")
+	}
+
+	dmChannelsMu.Lock()
+	channelID, ok := dmChannels[target.DiscordID]
+	dmChannelsMu.Unlock()
+	if !ok {
+		var err error
+		channelID, err = client.CreateDMChannel(target.DiscordID)
+		if err != nil {
+			return fmt.Errorf("failed to open DM channel with %s: %w", target.Username, err)
+		}
+		dmChannelsMu.Lock()
+		dmChannels[target.DiscordID] = channelID
+		dmChannelsMu.Unlock()
+	}
+
+	message := types.Message{Content: payload.Content, Embeds: payload.Embeds}
+	if err := client.CreateMessage(channelID, &message); err != nil {
+		return fmt.Errorf("failed to send DM to %s: %w", target.Username, err)
+	}
+
+	fmt.Printf("✅ Direct message sent to %s (%s) successfully\n", target.Username, target.DiscordID)
+	return nil
+}
+
+// deliver sends a player notification according to the player's notification preference.
+// If a direct message cannot be sent to a player who only wants DMs, the channel webhook is used instead
+// so the notice is never lost.
+func deliver(payload *types.DiscordWebhook, target userparser.UserMapping) error {
+	switch target.Notify {
+	case userparser.NotifyDM:
+		err := sendDirectMessage(payload, target)
+		if err == nil {
+			return nil
+		}
+		fmt.Printf("⚠️ %v, falling back to the channel webhook\n", err)
+		return sendDiscordWebhook(payload, target.Username, target.DiscordID, false)
+	case userparser.NotifyBoth:
+		return errors.Join(
+			sendDiscordWebhook(payload, target.Username, target.DiscordID, false),
+			sendDirectMessage(payload, target),
+		)
+	default:
+		return sendDiscordWebhook(payload, target.Username, target.DiscordID, false)
+	}
+}
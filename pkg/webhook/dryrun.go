@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// dryRun holds the dry-run setting. While it is enabled, notifications are rendered but not sent.
+var dryRun struct {
+	mu      sync.Mutex
+	enabled bool
+	path    string // File dry-run notifications are appended to, empty to log them instead.
+}
+
+// DryRunRecord is a notification that was rendered but not sent, as written to the dry-run file.
+type DryRunRecord struct {
+	Time      time.Time             `json:"time"`
+	Backend   string                `json:"backend"`   // webhook or dm
+	Recipient string                `json:"recipient"` // Username of the player, or "game channel" for announcements.
+	Payload   *types.DiscordWebhook `json:"payload"`
+}
+
+// setDryRun enables or disables dry-run mode. While enabled, notifications are appended to path as
+// JSON Lines, or logged in full if path is empty, instead of being sent to Discord.
+func setDryRun(enabled bool, path string) {
+	dryRun.mu.Lock()
+	defer dryRun.mu.Unlock()
+	dryRun.enabled = enabled
+	dryRun.path = path
+}
+
+// DryRun reports whether dry-run mode is enabled.
+func DryRun() bool {
+	dryRun.mu.Lock()
+	defer dryRun.mu.Unlock()
+	return dryRun.enabled
+}
+
+// recordDryRun logs or writes a notification instead of sending it, and records it as delivered
+// with the status "dry_run".
+func recordDryRun(backend, recipient string, payload *types.DiscordWebhook) error {
+	record := DryRunRecord{
+		Time:      now(),
+		Backend:   backend,
+		Recipient: recipient,
+		Payload:   payload,
+	}
+
+	dryRun.mu.Lock()
+	path := dryRun.path
+	dryRun.mu.Unlock()
+
+	var err error
+	if path == "" {
+		var rendered []byte
+		if rendered, err = encodeDryRun(record, "  "); err == nil {
+			fmt.Printf("🧪 Dry run, not sending %s notification to %s:\n%s", backend, recipient, rendered)
+		}
+	} else {
+		err = appendDryRun(path, record)
+		if err == nil {
+			fmt.Printf("🧪 Dry run, wrote %s notification to %s to %s\n", backend, recipient, path)
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to record dry-run notification: %w", err)
+	}
+	recordDelivery(backend, recipient, payload, "dry_run", err)
+	return err
+}
+
+// appendDryRun appends a dry-run record to the file at path.
+func appendDryRun(path string, record DryRunRecord) error {
+	line, err := encodeDryRun(record, "")
+	if err != nil {
+		return err
+	}
+
+	dryRun.mu.Lock()
+	defer dryRun.mu.Unlock()
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return err
+		}
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// encodeDryRun renders a dry-run record as JSON followed by a newline, leaving mentions such as
+// <@123> unescaped so they read as they would in Discord.
+func encodeDryRun(record DryRunRecord, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", indent)
+	if err := encoder.Encode(record); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package webhook
+
+import (
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+// Notifier receives rendered notifications in place of Discord, for example to record them in a
+// simulation.
+type Notifier interface {
+	// Notify delivers a payload. backend is "webhook" for the game channel or "dm" for a direct
+	// message, and recipient is the player's username or "game channel" for announcements.
+	Notify(backend, recipient string, payload *types.DiscordWebhook) error
+}
+
+// Clock tells the package the time for message timestamps and the delivery log, like the monitor's clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// injected is the notifier set with SetNotifier and the clock set with SetClock
+var injected struct {
+	mu       sync.Mutex
+	notifier Notifier
+	clock    Clock
+}
+
+// SetNotifier sends every notification to n instead of Discord, or to Discord again if n is nil.
+// It takes precedence over dry-run mode.
+func SetNotifier(n Notifier) {
+	injected.mu.Lock()
+	defer injected.mu.Unlock()
+	injected.notifier = n
+}
+
+// SetClock timestamps notifications with clock instead of the system time, or with the system time
+// again if clock is nil. A simulation uses it so messages carry the simulated time.
+func SetClock(clock Clock) {
+	injected.mu.Lock()
+	defer injected.mu.Unlock()
+	injected.clock = clock
+}
+
+// now returns the current time according to the injected clock, or the system time if there isn't one.
+func now() time.Time {
+	injected.mu.Lock()
+	clock := injected.clock
+	injected.mu.Unlock()
+	if clock == nil {
+		return time.Now()
+	}
+	return clock.Now()
+}
+
+// intercept hands a notification to the injected notifier, or records it in a dry run, instead of
+// sending it. It reports whether the notification was intercepted.
+func intercept(backend, recipient string, payload *types.DiscordWebhook) (bool, error) {
+	injected.mu.Lock()
+	n := injected.notifier
+	injected.mu.Unlock()
+
+	switch {
+	case n != nil:
+		return true, n.Notify(backend, recipient, payload)
+	case DryRun():
+		return true, recordDryRun(backend, recipient, payload)
+	default:
+		return false, nil
+	}
+}
@@ -7,15 +7,73 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/metrics"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
 )
 
+// settings holds the configuration notifications are sent with, set with Configure.
+var settings struct {
+	mu         sync.RWMutex
+	gameName   string // Game name as configured, used in messages and save names.
+	webhookURL string // Channel webhook notifications are posted to.
+	botToken   string // Bot token direct messages are sent with.
+	apiBaseURL string // Discord API direct messages are sent through, empty for the default.
+	botMode    bool   // Whether the /skip slash command is available.
+	adminAPI   bool   // Whether the admin API's advance endpoint is available.
+}
+
+// Configure sets the game name, channel webhook, direct message credentials, dry-run mode and available
+// admin commands notifications are sent with from cfg. It is called at startup and whenever the
+// configuration is reloaded.
+func Configure(cfg *config.Config) {
+	settings.mu.Lock()
+	settings.gameName = cfg.GameName
+	settings.webhookURL = cfg.WebhookURL
+	settings.botToken = cfg.BotToken
+	settings.apiBaseURL = cfg.APIBaseURL
+	settings.botMode = cfg.PublicKey != ""
+	settings.adminAPI = cfg.StatusListenAddr != "" && cfg.AdminToken != ""
+	settings.mu.Unlock()
+	setDryRun(cfg.DryRun, cfg.DryRunFile)
+}
+
+// gameName returns the configured game name, or the default if Configure hasn't been called.
+func gameName() string {
+	settings.mu.RLock()
+	defer settings.mu.RUnlock()
+	if settings.gameName == "" {
+		return "pbem1"
+	}
+	return settings.gameName
+}
+
+// skipCommand names the way an admin can skip the current player, for messages asking them to: /skip in
+// bot mode, otherwise the admin API. It returns an empty string if neither is enabled.
+func skipCommand() string {
+	settings.mu.RLock()
+	defer settings.mu.RUnlock()
+	switch {
+	case settings.botMode:
+		return "`/skip`"
+	case settings.adminAPI:
+		return "the admin API's `advance` endpoint"
+	}
+	return ""
+}
+
 // prepareWebhookURL adds the wait=true parameter to the webhook URL
 func prepareWebhookURL() (string, error) {
-	webhookURL := os.Getenv("DISCORD_WEBHOOK_URL")
+	settings.mu.RLock()
+	webhookURL := settings.webhookURL
+	settings.mu.RUnlock()
 
 	if webhookURL == "" {
 		fmt.Println("❌ DISCORD_WEBHOOK_URL environment variable is not set")
@@ -36,19 +94,21 @@ func prepareWebhookURL() (string, error) {
 	return parsedURL.String(), nil
 }
 
-// getGameName returns the configured game name or the default
-func getGameName() string {
-	gameName := os.Getenv("GAME_NAME")
-	if gameName == "" {
-		return "pbem1"
-	}
-	return gameName
-}
-
 // sendDiscordWebhook sends a webhook with retry logic and status code handling
 func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID string, isRename bool) error {
+	if err := beginSend(); err != nil {
+		fmt.Printf("⚠️ Not sending notification for %s: %v\n", username, err)
+		return err
+	}
+	defer endSend()
+
+	if intercepted, err := intercept("webhook", username, payload); intercepted {
+		return err
+	}
+
 	webhookURL, err := prepareWebhookURL()
 	if err != nil {
+		recordDelivery("webhook", username, payload, "error", err)
 		return err
 	}
 
@@ -69,7 +129,9 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 				time.Sleep(time.Duration(attempt) * time.Second)
 				continue
 			}
-			return fmt.Errorf("failed to send Discord notification after %d attempts: %w", maxRetries, err)
+			err = fmt.Errorf("failed to send Discord notification after %d attempts: %w", maxRetries, err)
+			recordDelivery("webhook", username, payload, "error", err)
+			return err
 		}
 
 		// Read response body for debugging
@@ -77,6 +139,7 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 		resp.Body.Close()
 
 		// Handle different status codes
+		status := strconv.Itoa(resp.StatusCode)
 		switch resp.StatusCode {
 		case 204:
 			msgType := "notification"
@@ -85,6 +148,7 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 			}
 			fmt.Printf("ℹ️ Discord returned status 204 for %s to %s (%s)\n", msgType, username, discordID)
 			fmt.Printf("ℹ️ This usually means the webhook was accepted but verify it appeared in Discord\n")
+			recordDelivery("webhook", username, payload, status, nil)
 			return nil
 		case 200:
 			msgType := ""
@@ -92,15 +156,19 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 				msgType = "Rename "
 			}
 			fmt.Printf("✅ %snotification sent to %s (%s) successfully\n", msgType, username, discordID)
+			recordDelivery("webhook", username, payload, status, nil)
 			return nil
 		case 429:
 			fmt.Printf("⚠️ Attempt %d: Discord rate limit hit (429). Response: %s\n", attempt, string(body))
+			metrics.RateLimitHits.Inc("webhook")
 			if attempt < maxRetries {
 				// Wait longer between retries on rate limit
 				time.Sleep(time.Duration(attempt*3) * time.Second)
 				continue
 			}
-			return fmt.Errorf("discord rate limit exceeded after %d attempts", maxRetries)
+			err := fmt.Errorf("discord rate limit exceeded after %d attempts", maxRetries)
+			recordDelivery("webhook", username, payload, status, err)
+			return err
 		default:
 			fmt.Printf("❌ Attempt %d: Discord returned unexpected status %d. Response: %s\n",
 				attempt, resp.StatusCode, string(body))
@@ -108,79 +176,465 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 				time.Sleep(time.Duration(attempt) * time.Second)
 				continue
 			}
-			return fmt.Errorf("discord returned status %d after %d attempts: %s",
+			err := fmt.Errorf("discord returned status %d after %d attempts: %s",
 				resp.StatusCode, maxRetries, string(body))
+			recordDelivery("webhook", username, payload, status, err)
+			return err
 		}
 	}
 
 	return fmt.Errorf("failed to send Discord notification after %d attempts", maxRetries)
 }
 
-// SendWebHook sends a Discord webhook notification to the next player
-// targetUsername/targetDiscordID: The player whose turn it is now (will be pinged)
-// nextPlayerSaveName: The username of the player *after* the target player (used for save instructions)
-func SendWebHook(targetUsername, targetDiscordID, nextPlayerSaveName string, turnNumber int) error {
-	gameName := getGameName()
-
-	// Create webhook payload
-	payload := types.DiscordWebhook{
+// newPayload builds a webhook payload with the standard bot identity, thumbnail and footer.
+func newPayload(content string, color int, fields ...types.Field) types.DiscordWebhook {
+	return types.DiscordWebhook{
 		Username:  "Shadow Empire Assistant",
 		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
-		Content:   fmt.Sprintf("🎲 It's your turn, <@%s>!", targetDiscordID), // Ping the target player
+		Content:   content,
 		Embeds: []types.Embed{
 			{
-				Color: 0xFFA500,
+				Color: color,
 				Thumbnail: types.Thumbnail{
 					URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
 				},
-				Fields: []types.Field{
-					{
-						Name: "📋 Save File Instructions",
-						// Instruct to save for the player *after* the current one
-						Value: fmt.Sprintf("After completing your turn, please save the file as:\n```\n%s_turn%d_%s\n```", gameName, turnNumber, nextPlayerSaveName),
-					},
-				},
+				Fields: fields,
 				Footer: types.Footer{
 					Text: "Made with ❤️ by Solon",
 				},
-				Timestamp: time.Now().Format(time.RFC3339),
+				Timestamp: now().Format(time.RFC3339),
 			},
 		},
 	}
+}
+
+// SendWebHook sends a Discord notification to the next player, via the channel webhook and/or
+// a direct message depending on their preference
+// target: The player whose turn it is now (will be pinged)
+// nextPlayerSaveName: The username of the player *after* the target player (used for save instructions)
+func SendWebHook(target userparser.UserMapping, nextPlayerSaveName string, turnNumber int) error {
+	game := gameName()
+
+	// Ping the target player and instruct them to save for the player *after* them
+	payload := newPayload(
+		fmt.Sprintf("🎲 It's your turn, <@%s>!", target.DiscordID),
+		0xFFA500,
+		types.Field{
+			Name:  "📋 Save File Instructions",
+			Value: fmt.Sprintf("After completing your turn, please save the file as:\n```\n%s_turn%d_%s\n```", game, turnNumber, nextPlayerSaveName),
+		},
+	)
 
-	// Pass targetUsername for logging purposes in sendDiscordWebhook
-	return sendDiscordWebhook(&payload, targetUsername, targetDiscordID, false)
+	return deliver(&payload, target)
 }
 
-// SendRenameWebHook sends a Discord webhook notification asking to rename a file
-func SendRenameWebHook(username, discordID, filename string, turnNumber int) error {
-	gameName := getGameName()
+// SendRenameWebHook sends a Discord notification asking the player who made a misnamed save to rename it
+// saver: The player who made the save (will be pinged)
+// expectedName: The exact filename the save should have, including the next player's name
+func SendRenameWebHook(saver userparser.UserMapping, filename, expectedName string) error {
+	payload := newPayload(
+		fmt.Sprintf("⚠️ File naming issue detected in your save, <@%s>!", saver.DiscordID),
+		0xFF0000, // Red color for warning
+		types.Field{
+			Name: "📋 File Rename Required",
+			Value: fmt.Sprintf("The save file you created `%s` doesn't match the configured game name.\n\nPlease rename it to:\n```\n%s\n```\nThe next player will be notified as soon as the renamed file appears.",
+				filename, expectedName),
+		},
+	)
 
-	// Create webhook payload
-	payload := types.DiscordWebhook{
-		Username:  "Shadow Empire Assistant",
-		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
-		Content:   fmt.Sprintf("⚠️ File naming issue detected in your save, <@%s>!", discordID),
-		Embeds: []types.Embed{
-			{
-				Color: 0xFF0000, // Red color for warning
-				Thumbnail: types.Thumbnail{
-					URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
-				},
-				Fields: []types.Field{
-					{
-						Name: "📋 File Rename Required",
-						Value: fmt.Sprintf("The save file you created `%s` doesn't match the configured game name.\n\nPlease rename it to follow the format:\n```\n%s_turn%d_%s\n```\n*(Replace %s with the next player's name)*",
-							filename, gameName, turnNumber, "[NextPlayerName]", "[NextPlayerName]"),
-					},
-				},
-				Footer: types.Footer{
-					Text: "Made with ❤️ by Solon",
-				},
-				Timestamp: time.Now().Format(time.RFC3339),
-			},
+	return deliver(&payload, saver)
+}
+
+// SendFileAgeWarningWebHook warns that the latest save has not changed for longer than the configured limit.
+// target identifies the player believed to be holding the turn; it is the zero value if unknown, in which
+// case the warning is only posted to the channel.
+func SendFileAgeWarningWebHook(filename string, fileAge, fileAgeLimit time.Duration, target userparser.UserMapping) error {
+	content := fmt.Sprintf("⏰ No new save for %s", fileAge.Round(time.Minute))
+	if target.DiscordID != "" {
+		content = fmt.Sprintf("⏰ Friendly reminder, <@%s>: it's still your turn!", target.DiscordID)
+	}
+
+	payload := newPayload(
+		content,
+		0xFFFF00, // Yellow color for reminders
+		types.Field{
+			Name:  "🕰️ Waiting On Turn",
+			Value: fmt.Sprintf("The latest save `%s` is %s old, which exceeds the limit of %s.", filename, fileAge.Round(time.Minute), fileAgeLimit),
+		},
+	)
+
+	if target.DiscordID == "" {
+		return sendDiscordWebhook(&payload, "", "", false)
+	}
+	return deliver(&payload, target)
+}
+
+// SendReminderWebHook pings the player whose turn it is with how long they have had it
+func SendReminderWebHook(target userparser.UserMapping, turnNumber int, waiting time.Duration) error {
+	payload := newPayload(
+		fmt.Sprintf("⏰ Friendly reminder, <@%s>: it's still your turn!", target.DiscordID),
+		0xFFFF00, // Yellow color for reminders
+		types.Field{
+			Name:  "🕰️ Waiting On Turn",
+			Value: fmt.Sprintf("Turn %d has been waiting on you for %s.", turnNumber, waiting.Round(time.Minute)),
+		},
+	)
+
+	return deliver(&payload, target)
+}
+
+// SendSkipWebHook tells the next player that the previous player was skipped and that they should
+// continue from the latest save.
+// skippedUsername: The player who was skipped
+// target: The player who now has the turn (will be pinged)
+// nextPlayerSaveName: The username of the player *after* the target player (used for save instructions)
+func SendSkipWebHook(skippedUsername string, target userparser.UserMapping, nextPlayerSaveName, lastSave string, turnNumber int) error {
+	game := gameName()
+
+	payload := newPayload(
+		fmt.Sprintf("⏭️ %s has been skipped. It's your turn, <@%s>!", skippedUsername, target.DiscordID),
+		0xFFA500,
+		types.Field{
+			Name:  "📂 Load This Save",
+			Value: fmt.Sprintf("Continue from the latest save:\n```\n%s\n```", lastSave),
+		},
+		types.Field{
+			Name:  "📋 Save File Instructions",
+			Value: fmt.Sprintf("After completing your turn, please save the file as:\n```\n%s_turn%d_%s\n```", game, turnNumber, nextPlayerSaveName),
+		},
+	)
+
+	return deliver(&payload, target)
+}
+
+// SendDeadlineWarningWebHook gives the current player a final warning that they will be skipped
+// if they don't take their turn in time.
+func SendDeadlineWarningWebHook(target userparser.UserMapping, turnNumber int, remaining time.Duration) error {
+	payload := newPayload(
+		fmt.Sprintf("⌛ Final warning, <@%s>: you have %s left to play turn %d!", target.DiscordID, remaining.Round(time.Minute), turnNumber),
+		0xFF8C00, // Dark orange for deadlines
+		types.Field{
+			Name:  "⏭️ Deadline",
+			Value: "If your save hasn't arrived by then, you will be skipped for this round and the next player will continue from the latest save.",
+		},
+	)
+
+	return deliver(&payload, target)
+}
+
+// SendDeadlineMissedWebHook tells the current player they missed the deadline and are being skipped.
+func SendDeadlineMissedWebHook(target userparser.UserMapping, turnNumber int, limit time.Duration) error {
+	payload := newPayload(
+		fmt.Sprintf("⌛ <@%s>, turn %d wasn't played within %s, so you have been skipped for this round.", target.DiscordID, turnNumber, limit),
+		0xFF0000, // Red for a missed deadline
+	)
+
+	return deliver(&payload, target)
+}
+
+// SendDeadlineSkipLimitWebHook tells the game channel that a player missed the deadline but has used
+// up their skips, so the turn stays with them.
+func SendDeadlineSkipLimitWebHook(target userparser.UserMapping, turnNumber, skips int) error {
+	action := "The turn stays with them until they play it."
+	if command := skipCommand(); command != "" {
+		action += fmt.Sprintf(" Use %s to pass it on anyway.", command)
+	}
+	payload := newPayload(
+		fmt.Sprintf("⌛ <@%s> missed the deadline for turn %d but has already been skipped %d times.", target.DiscordID, turnNumber, skips),
+		0xFF0000, // Red for a missed deadline
+		types.Field{
+			Name:  "🛠️ Action Needed",
+			Value: action,
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "skip limit announcement", false)
+}
+
+// SendConflictWebHook alerts the game channel that a sync tool created a conflict copy of a save
+func SendConflictWebHook(conflictFilename, originalFilename string) error {
+	payload := newPayload(
+		"⚔️ A sync conflict copy of a save has appeared!",
+		0xFF0000, // Red color for warning
+		types.Field{
+			Name: "📂 Conflicting Saves",
+			Value: fmt.Sprintf("Your sync tool created `%s` as a conflict copy of `%s`.\n\nTwo different versions of this save exist. Please check which one is correct, delete the other and make sure only one save for this turn remains.",
+				conflictFilename, originalFilename),
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "conflict alert", false)
+}
+
+// SendSequenceWebHook alerts the game channel that a save doesn't fit the turn order and was not handed over
+// expectedName: The save the game is waiting for
+func SendSequenceWebHook(filename, problem, expectedName string) error {
+	expected := fmt.Sprintf("The game is waiting for:\n```\n%s\n```\nPlease remove or rename the wrong file.", expectedName)
+	if command := skipCommand(); command != "" {
+		expected += fmt.Sprintf(" If a player is being passed over on purpose, use %s first and then save again.", command)
+	}
+	payload := newPayload(
+		"🧭 A save arrived out of order!",
+		0xFFA500, // Orange color for attention
+		types.Field{
+			Name:  "📂 Save Not Handed Over",
+			Value: fmt.Sprintf("`%s` was ignored because %s.", filename, problem),
+		},
+		types.Field{
+			Name:  "📋 Expected Save",
+			Value: expected,
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "out-of-order save alert", false)
+}
+
+// SendAdminActionWebHook announces an administrative change to the game channel
+// actor: Who made the change, as reported to the admin API
+func SendAdminActionWebHook(actor, description string) error {
+	payload := newPayload(
+		"🛠️ An admin changed the game",
+		0x1E90FF, // Blue color for admin actions
+		types.Field{
+			Name:  "📋 Change",
+			Value: fmt.Sprintf("%s\n\nRequested by %s.", description, actor),
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "admin announcement", false)
+}
+
+// SendConfigReloadWebHook announces in the game channel that the bot's configuration was reloaded
+// changes: One line per changed setting
+func SendConfigReloadWebHook(changes []string) error {
+	payload := newPayload(
+		"🔁 The bot's configuration was reloaded",
+		0x1E90FF, // Blue color for admin actions
+		types.Field{
+			Name:  "📋 Changes",
+			Value: "```\n" + strings.Join(changes, "\n") + "\n```",
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "config reload announcement", false)
+}
+
+// SendPauseWebHook announces in the game channel that the game is paused
+// until: When the game resumes by itself, zero if no date was set
+func SendPauseWebHook(until time.Time, reason string) error {
+	resume := "It will resume when an admin resumes it."
+	if !until.IsZero() {
+		resume = fmt.Sprintf("It will resume automatically <t:%d:F> (<t:%d:R>).", until.Unix(), until.Unix())
+	}
+	description := "No reminders will be sent and the paused time won't count towards anyone's turn."
+	if reason != "" {
+		description = fmt.Sprintf("Reason: %s\n\n%s", reason, description)
+	}
+
+	payload := newPayload(
+		"⏸️ The game is paused",
+		0x808080, // Grey color for paused
+		types.Field{Name: "📋 Pause", Value: description},
+		types.Field{Name: "📅 Resuming", Value: resume},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "pause announcement", false)
+}
+
+// SendResumeWebHook announces in the game channel that the game has resumed
+// current: The player whose turn it is (will be pinged), or the zero value if no save has been processed yet
+func SendResumeWebHook(current userparser.UserMapping, pausedFor time.Duration) error {
+	content := "▶️ The game has resumed!"
+	if current.DiscordID != "" {
+		content = fmt.Sprintf("▶️ The game has resumed, it's still your turn <@%s>!", current.DiscordID)
+	}
+
+	payload := newPayload(
+		content,
+		0x00FF00, // Green color for resumed
+		types.Field{
+			Name:  "📋 Resumed",
+			Value: fmt.Sprintf("The game was paused for %s. Reminders are back on.", pausedFor.Round(time.Minute)),
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "resume announcement", false)
+}
+
+// SendStatsWebHook posts a summary of each player's turn times since the given time to the game
+// channel, naming the slowest player.
+func SendStatsWebHook(stats []history.PlayerStats, since time.Time) error {
+	content := fmt.Sprintf("📊 Turn summary for %s since %s", gameName(), since.Format("Mon 2 Jan"))
+
+	var fields []types.Field
+	if slowest, ok := history.Slowest(stats); ok {
+		fields = append(fields, types.Field{
+			Name:  "🐢 Slowest player",
+			Value: fmt.Sprintf("%s, averaging %s per turn", slowest.Player, slowest.Average.Round(time.Minute)),
+		})
+	}
+	for _, s := range stats {
+		value := "No turns played"
+		if s.Turns > 0 {
+			value = fmt.Sprintf("%d turns, average %s, median %s, longest %s",
+				s.Turns, s.Average.Round(time.Minute), s.Median.Round(time.Minute), s.Longest.Round(time.Minute))
+		}
+		if s.Reminders > 0 || s.Skips > 0 {
+			value += fmt.Sprintf("\n%d reminders, %d skips", s.Reminders, s.Skips)
+		}
+		fields = append(fields, types.Field{Name: s.Player, Value: value})
+	}
+
+	payload := newPayload(content, 0x5865F2, fields...) // Blurple for summaries
+	return sendDiscordWebhook(&payload, "game channel", "turn summary", false)
+}
+
+// SendRoundSummaryWebHook posts how long each player took in a completed round, and the round's total time.
+func SendRoundSummaryWebHook(round int, turns []history.Turn) error {
+	content := fmt.Sprintf("🏁 Round %d of %s is complete!", round, gameName())
+
+	var fields []types.Field
+	for _, turn := range turns {
+		value := turn.Duration().Round(time.Minute).String()
+		if turn.Skipped {
+			value = fmt.Sprintf("Skipped after %s", value)
+		}
+		if turn.Reminders > 0 {
+			value += fmt.Sprintf(" (%d reminders)", turn.Reminders)
+		}
+		fields = append(fields, types.Field{Name: turn.Player, Value: value})
+	}
+	fields = append(fields, types.Field{
+		Name:  "⏱️ Round time",
+		Value: history.Span(turns).Round(time.Minute).String(),
+	})
+
+	payload := newPayload(content, 0x5865F2, fields...) // Blurple for summaries
+	return sendDiscordWebhook(&payload, "game channel", "round summary", false)
+}
+
+// SendDigestWebHook posts a digest of the game since the given time: the turns played, who is up
+// now and each player's turn times.
+func SendDigestWebHook(since time.Time, turns []history.Turn, stats []history.PlayerStats, currentTurn int, current string, waiting time.Duration) error {
+	content := fmt.Sprintf("📰 %s digest since %s", gameName(), since.Format("Mon 2 Jan"))
+
+	played := "No turns were played"
+	if len(turns) > 0 {
+		played = fmt.Sprintf("%d turns played, from turn %d to turn %d", len(turns), turns[0].Turn, turns[len(turns)-1].Turn)
+	}
+	fields := []types.Field{{Name: "📅 Progress", Value: played}}
+
+	if current != "" {
+		fields = append(fields, types.Field{
+			Name:  "⏳ Up now",
+			Value: fmt.Sprintf("%s on turn %d, waiting %s", current, currentTurn, waiting.Round(time.Minute)),
+		})
+	}
+	if slowest, ok := history.Slowest(stats); ok {
+		fields = append(fields, types.Field{
+			Name:  "🐢 Slowest player",
+			Value: fmt.Sprintf("%s, averaging %s per turn", slowest.Player, slowest.Average.Round(time.Minute)),
+		})
+	}
+
+	var lines []string
+	for _, s := range stats {
+		line := fmt.Sprintf("%s: %d turns", s.Player, s.Turns)
+		if s.Turns > 0 {
+			line += fmt.Sprintf(", average %s", s.Average.Round(time.Minute))
+		}
+		if s.Skips > 0 {
+			line += fmt.Sprintf(", %d skips", s.Skips)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > 0 {
+		fields = append(fields, types.Field{Name: "👥 Players", Value: strings.Join(lines, "\n")})
+	}
+
+	payload := newPayload(content, 0x5865F2, fields...) // Blurple for summaries
+	return sendDiscordWebhook(&payload, "game channel", "digest", false)
+}
+
+// SendInvalidSaveWebHook asks the player who made a save to save again because the file was rejected
+func SendInvalidSaveWebHook(saver userparser.UserMapping, filename string, problems []string) error {
+	payload := newPayload(
+		fmt.Sprintf("🚫 Your save couldn't be used, <@%s>!", saver.DiscordID),
+		0xFF0000, // Red color for warning
+		types.Field{
+			Name:  "📂 Rejected Save",
+			Value: fmt.Sprintf("`%s` was not handed over to the next player:\n- %s\n\nPlease save your turn again under the same name.", filename, strings.Join(problems, "\n- ")),
+		},
+	)
+
+	return deliver(&payload, saver)
+}
+
+// SendRollbackWebHook announces that the game was rolled back and tells the player who is now up which save to load
+// target: The player whose turn it is after the rollback (will be pinged)
+// nextPlayerSaveName: The username of the player *after* the target player (used for save instructions)
+func SendRollbackWebHook(target userparser.UserMapping, nextPlayerSaveName, restoredSave string, turnNumber, saveTurnNumber int) error {
+	game := gameName()
+
+	payload := newPayload(
+		fmt.Sprintf("⏪ Game rolled back to turn %d, <@%s> is up!", turnNumber, target.DiscordID),
+		0x1E90FF, // Blue color for admin actions
+		types.Field{
+			Name:  "📂 Load This Save",
+			Value: fmt.Sprintf("The save has been restored from the archive:\n```\n%s\n```", restoredSave),
 		},
+		types.Field{
+			Name:  "📋 Save File Instructions",
+			Value: fmt.Sprintf("After completing your turn, please save the file as:\n```\n%s_turn%d_%s\n```", game, saveTurnNumber, nextPlayerSaveName),
+		},
+	)
+
+	return deliver(&payload, target)
+}
+
+// SendAutoRenameWebHook tells the player who made a misnamed save that the bot fixed the name for them
+func SendAutoRenameWebHook(saver userparser.UserMapping, oldName, newName string, copied bool) error {
+	action := "renamed"
+	if copied {
+		action = "copied"
 	}
 
-	return sendDiscordWebhook(&payload, username, discordID, true)
+	payload := newPayload(
+		fmt.Sprintf("✏️ I fixed the name of your save, <@%s>!", saver.DiscordID),
+		0x1E90FF, // Blue color for informational messages
+		types.Field{
+			Name:  "📋 Save File Renamed",
+			Value: fmt.Sprintf("`%s` didn't match the game's naming format, so I %s it to:\n```\n%s\n```\nNo action is needed, the next player has been notified.", oldName, action, newName),
+		},
+	)
+
+	return deliver(&payload, saver)
+}
+
+// SendTestWebHook posts a test message to the game channel, to check the webhook is set up
+func SendTestWebHook() error {
+	payload := newPayload(
+		fmt.Sprintf("🧪 Test message from the %s bot", gameName()),
+		0x1E90FF, // Blue color for informational messages
+		types.Field{
+			Name:  "📋 Test",
+			Value: "If you can read this, turn notifications will be posted to this channel.",
+		},
+	)
+
+	return sendDiscordWebhook(&payload, "game channel", "test message", false)
+}
+
+// SendTestDirectMessage sends a test direct message to a player, to check the bot can reach them
+func SendTestDirectMessage(target userparser.UserMapping) error {
+	payload := newPayload(
+		fmt.Sprintf("🧪 Test message from the %s bot", gameName()),
+		0x1E90FF, // Blue color for informational messages
+		types.Field{
+			Name:  "📋 Test",
+			Value: "If you can read this, you will get your turn notifications as direct messages.",
+		},
+	)
+
+	return sendDirectMessage(&payload, target)
 }
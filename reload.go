@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+	"github.com/joho/godotenv"
+)
+
+// envFile is the .env file the environment was loaded from. A reload reads the file again without
+// changing the environment, so the new values only reach the bot through its configuration.
+type envFile struct {
+	path   string
+	system map[string]bool // Variables set before the file was loaded, which the file doesn't override.
+}
+
+// newEnvFile records the environment before path is loaded, so a reload can tell which variables
+// came from the file.
+func newEnvFile(path string) *envFile {
+	env := &envFile{path: path, system: make(map[string]bool)}
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		env.system[name] = true
+	}
+	return env
+}
+
+// lookup returns a function that reads variables as they would be after loading values from the file:
+// the system's take precedence, the same as at startup.
+func (e *envFile) lookup(values map[string]string) func(string) string {
+	return func(name string) string {
+		if e.system[name] {
+			return os.Getenv(name)
+		}
+		return values[name]
+	}
+}
+
+// keepDryRun wraps lookup so that a bot started with --dry-run stays in a dry run.
+func keepDryRun(lookup func(string) string, dryRun bool) func(string) string {
+	return func(name string) string {
+		if dryRun && name == "DRY_RUN" {
+			return "true"
+		}
+		return lookup(name)
+	}
+}
+
+// modified returns when the file was last modified, or the zero time if it can't be read.
+func (e *envFile) modified() time.Time {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// watchConfig reloads the configuration when the .env file changes or a SIGHUP is received, until ctx
+// is done, and passes each new configuration to apply. env is nil if the configuration came from the
+// system, in which case it is only read again on a SIGHUP. dryRun is set if the bot was started with --dry-run.
+func watchConfig(ctx context.Context, env *envFile, apply func(*config.Config), current *config.Config, dryRun bool) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	if env != nil {
+		fmt.Printf("🔁 Watching %s for configuration changes\n", env.path)
+	}
+	var lastModified time.Time
+	if env != nil {
+		lastModified = env.modified()
+	}
+
+	ticker := time.NewTicker(monitor.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			fmt.Println("🔁 SIGHUP received, reloading the configuration")
+			if env != nil {
+				lastModified = env.modified()
+			}
+			current = reloadConfig(env, apply, current, dryRun)
+		case <-ticker.C:
+			if env == nil {
+				continue
+			}
+			if modified := env.modified(); !modified.IsZero() && !modified.Equal(lastModified) {
+				fmt.Printf("🔁 %s changed, reloading the configuration\n", env.path)
+				lastModified = modified
+				current = reloadConfig(env, apply, current, dryRun)
+			}
+		}
+	}
+}
+
+// reloadConfig reads the configuration from env, or the system environment if env is nil, and applies it
+// to the running bot, returning the configuration now in use. Nothing is changed if the new configuration
+// has any problems.
+func reloadConfig(env *envFile, apply func(*config.Config), current *config.Config, dryRun bool) *config.Config {
+	lookup := os.Getenv
+	if env != nil {
+		values, err := godotenv.Read(env.path)
+		if err != nil {
+			fmt.Printf("⚠️ Failed to read %s, keeping the current configuration: %v\n", env.path, err)
+			return current
+		}
+		lookup = env.lookup(values)
+	}
+
+	cfg, err := config.Load(keepDryRun(lookup, dryRun))
+	if err != nil {
+		fmt.Println("⚠️ The new configuration has problems, keeping the current one:")
+		for _, problem := range problems(err) {
+			fmt.Printf("  - %s\n", problem)
+		}
+		return current
+	}
+
+	changes := config.Diff(current, cfg)
+
+	var announced []string
+	restartNeeded := false
+	for _, change := range changes {
+		if change.RequiresRestart() {
+			fmt.Printf("⚠️ %s, restart the bot to apply it\n", change)
+			restartNeeded = true
+			continue
+		}
+		fmt.Printf("🔁 %s\n", change)
+		announced = append(announced, change.String())
+	}
+	if len(announced) == 0 {
+		if !restartNeeded {
+			fmt.Println("🔁 Configuration reloaded, nothing changed")
+		}
+		return current
+	}
+
+	// These only change on a restart, so keep reporting them until then
+	cfg.KeepRestartOnly(current)
+
+	apply(cfg)
+	fmt.Printf("✅ Configuration reloaded, %d settings changed\n", len(announced))
+
+	if cfg.ReloadAnnounce {
+		if err := webhook.SendConfigReloadWebHook(announced); err != nil {
+			fmt.Printf("❌ Failed to announce the configuration change: %v\n", err)
+		}
+	}
+	return cfg
+}
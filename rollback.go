@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// runRollback restores an archived save and rewinds the game to it.
+// Usage: rollback <turn> <player>
+func runRollback(args []string) int {
+	if len(args) != 2 {
+		fmt.Println("Usage: shadow-empire-bot rollback <turn> <player>")
+		return 2
+	}
+
+	turn, err := strconv.Atoi(args[0])
+	if err != nil || turn < 1 {
+		fmt.Printf("❌ Invalid turn number: %s\n", args[0])
+		return 2
+	}
+
+	// Invalid values fall back to their defaults, so report them but carry on
+	cfg, err := config.FromEnv()
+	if err != nil {
+		for _, problem := range problems(err) {
+			fmt.Printf("⚠️ %s\n", problem)
+		}
+	}
+
+	webhook.Configure(cfg)
+	if err := monitor.Rollback(cfg, turn, args[1]); err != nil {
+		fmt.Printf("❌ Rollback failed: %v\n", err)
+		return 1
+	}
+
+	fmt.Printf("✅ Game rolled back to turn %d, %s is up\n", turn, args[1])
+	return 0
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/simulate"
+)
+
+// runSimulate replays a simulation script against the monitor and reports whether its
+// expectations were met. See the simulate package for the script format.
+// Usage: simulate <script>
+func runSimulate(args []string) int {
+	if len(args) != 1 {
+		fmt.Println("Usage: shadow-empire-bot simulate <script>")
+		return 2
+	}
+
+	script, err := os.Open(args[0])
+	if err != nil {
+		fmt.Printf("❌ Failed to open script: %v\n", err)
+		return 2
+	}
+	defer script.Close()
+
+	result, err := simulate.Run(script)
+	if err != nil {
+		fmt.Printf("❌ Simulation failed: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("📨 %d notifications sent\n", len(result.Notifications))
+	if !result.Passed() {
+		fmt.Printf("❌ %d of %d expectations failed:\n", len(result.Failures), result.Expectations)
+		for _, failure := range result.Failures {
+			fmt.Printf("  - %s\n", failure)
+		}
+		return 1
+	}
+	fmt.Printf("✅ All %d expectations met\n", result.Expectations)
+	return 0
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/history"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// runStats prints each player's turn time statistics from the turn history.
+// Usage: stats [days]
+func runStats(args []string) int {
+	if len(args) > 1 {
+		fmt.Println("Usage: shadow-empire-bot stats [days]")
+		return 2
+	}
+
+	var since time.Time
+	if len(args) == 1 {
+		days, err := strconv.Atoi(args[0])
+		if err != nil || days < 1 {
+			fmt.Printf("❌ Invalid number of days: %s\n", args[0])
+			return 2
+		}
+		since = time.Now().AddDate(0, 0, -days)
+	}
+
+	cfg, _ := config.FromEnv() // Problems are reported by validate; only the players are needed here
+	if cfg.HistoryFile == "" {
+		fmt.Println("❌ HISTORY_FILE is not set, so no turn history is kept to compute statistics from")
+		return 1
+	}
+	state, err := monitor.LoadGameState(cfg)
+	if err != nil {
+		fmt.Printf("❌ Failed to load game state: %v\n", err)
+		return 1
+	}
+	turns, err := state.TurnHistory()
+	if err != nil {
+		fmt.Printf("❌ Failed to read turn history: %v\n", err)
+		return 1
+	}
+
+	var players []string
+	for _, player := range state.Players() {
+		players = append(players, player.Username)
+	}
+	stats := history.Stats(turns, players, since)
+
+	if since.IsZero() {
+		fmt.Printf("📊 Turn statistics for %s\n\n", state.GameName())
+	} else {
+		fmt.Printf("📊 Turn statistics for %s since %s\n\n", state.GameName(), since.Format(time.DateOnly))
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PLAYER\tTURNS\tAVERAGE\tMEDIAN\tLONGEST\tREMINDERS\tSKIPS")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%d\t%d\n", s.Player, s.Turns,
+			s.Average.Round(time.Minute), s.Median.Round(time.Minute), s.Longest.Round(time.Minute), s.Reminders, s.Skips)
+	}
+	w.Flush()
+
+	if slowest, ok := history.Slowest(stats); ok {
+		fmt.Printf("\n🐢 Slowest player: %s (average %s)\n", slowest.Player, slowest.Average.Round(time.Minute))
+	} else {
+		fmt.Println("\nℹ️ No completed turns recorded yet")
+	}
+	return 0
+}
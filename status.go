@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// runStatus prints the current game state from the state file.
+// Usage: status
+func runStatus(args []string) int {
+	if len(args) != 0 {
+		fmt.Println("Usage: shadow-empire-bot status")
+		return 2
+	}
+
+	cfg, _ := config.FromEnv() // Problems are reported by validate; only the deadline is needed here
+	if cfg.StateFile == "" {
+		fmt.Println("❌ STATE_FILE is not set, so the bot keeps its state in memory and there is none to show")
+		return 1
+	}
+	state, err := monitor.LoadGameState(cfg)
+	if err != nil {
+		fmt.Printf("❌ Failed to load game state: %v\n", err)
+		return 1
+	}
+
+	snapshot := state.Snapshot()
+	snapshot.Deadline = cfg.TurnDeadline
+	now := time.Now()
+
+	fmt.Printf("🎲 Game: %s\n", snapshot.GameName)
+	fmt.Printf("🔢 Turn: %d\n", snapshot.CurrentTurn)
+	if current, ok := snapshot.CurrentMapping(); ok {
+		fmt.Printf("👤 Current player: %s (waiting %s)\n", current.Username, snapshot.Waiting(now).Round(time.Minute))
+		if dueAt, ok := snapshot.DueAt(now); ok {
+			fmt.Printf("⌛ Due: %s\n", dueAt.Local().Format(time.DateTime))
+		}
+		fmt.Printf("💾 Last save: %s\n", snapshot.LastSave)
+	} else {
+		fmt.Println("👤 Current player: none yet, waiting for the first save")
+	}
+
+	if snapshot.Paused {
+		line := fmt.Sprintf("⏸️ Paused since %s", snapshot.PausedAt.Local().Format(time.DateTime))
+		if !snapshot.PausedUntil.IsZero() {
+			line += fmt.Sprintf(", resuming %s", snapshot.PausedUntil.Local().Format(time.DateTime))
+		}
+		if snapshot.PauseReason != "" {
+			line += fmt.Sprintf(" (%s)", snapshot.PauseReason)
+		}
+		fmt.Println(line)
+	}
+	if snapshot.PendingRename != nil {
+		fmt.Printf("✏️ Waiting for %s to rename %s to %s\n", snapshot.PendingRename.Saver, snapshot.PendingRename.Original, snapshot.PendingRename.Expected)
+	}
+
+	fmt.Println("👥 Players:")
+	for i, player := range snapshot.Players {
+		marker := " "
+		if i == snapshot.CurrentPlayer {
+			marker = "▶"
+		}
+		note := ""
+		if snapshot.SittingOut[strings.ToLower(player.Username)] {
+			note = " (sitting out)"
+		}
+		fmt.Printf("  %s %d. %s%s\n", marker, player.Order, player.Username, note)
+	}
+	return 0
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/bot"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/config"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// runValidate checks the configuration, the watch directory and the saved state, printing each
+// problem found. It returns 1 if there were any.
+// Usage: validate
+func runValidate(args []string) int {
+	if len(args) != 0 {
+		fmt.Println("Usage: shadow-empire-bot validate")
+		return 2
+	}
+
+	failed := false
+	check := func(err error, ok string) {
+		if err != nil {
+			failed = true
+			for _, problem := range problems(err) {
+				fmt.Printf("❌ %s\n", problem)
+			}
+			return
+		}
+		fmt.Printf("✅ %s\n", ok)
+	}
+
+	cfg, err := config.FromEnv()
+	check(err, fmt.Sprintf("Configuration is valid: game %s with %d players", cfg.GameName, len(cfg.Players)))
+
+	entries, err := os.ReadDir(cfg.WatchDirectory)
+	if err != nil {
+		err = fmt.Errorf("watch directory %s can't be read: %w", cfg.WatchDirectory, err)
+	}
+	check(err, fmt.Sprintf("Watch directory %s is readable (%d entries)", cfg.WatchDirectory, len(entries)))
+
+	// The state can only be loaded with valid player mappings
+	if len(cfg.Players) == 0 {
+		return 1
+	}
+	state, err := monitor.LoadGameState(cfg)
+	if err != nil {
+		err = fmt.Errorf("state can't be loaded: %w", err)
+		check(err, "")
+		return 1
+	}
+	check(nil, fmt.Sprintf("State loaded: turn %d", state.Turn()))
+
+	if bot.Enabled(cfg) {
+		_, err := bot.New(state, cfg)
+		if err != nil {
+			err = fmt.Errorf("discord bot is misconfigured: %w", err)
+		}
+		check(err, "Discord bot is configured")
+	}
+
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// problems splits an error returned by config.Load into one line per problem.
+func problems(err error) []string {
+	return strings.Split(err.Error(), "\n")
+}